@@ -29,6 +29,9 @@ import (
 
 // ClusterGate is the Schema for the clustergates API
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="First Opened",type="date",JSONPath=`.status.firstOpenedTime`
+// +kubebuilder:printcolumn:name="Last Opened",type="date",JSONPath=`.status.lastOpenedTime`
+// +kubebuilder:printcolumn:name="Last Evaluation",type="date",JSONPath=`.status.lastEvaluationTime`
 type ClusterGate struct {
 	metav1.TypeMeta `json:",inline"`
 
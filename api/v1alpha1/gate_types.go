@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,6 +36,105 @@ type GateState = string
 const (
 	GateStateOpened GateState = "Opened"
 	GateStateClosed GateState = "Closed"
+	// GateStateFailed is the state of a gate which didn't open before its timeout.
+	GateStateFailed GateState = "Failed"
+)
+
+type GateTimeoutPolicy = string
+
+const (
+	// GateTimeoutPolicyStop stops the evaluation of a failed gate until its spec changes.
+	GateTimeoutPolicyStop GateTimeoutPolicy = "Stop"
+	// GateTimeoutPolicySlow evaluates a failed gate every evaluation period, opening it once valid.
+	GateTimeoutPolicySlow GateTimeoutPolicy = "Slow"
+)
+
+type GateSpecChangePolicy = string
+
+const (
+	// GateSpecChangePolicyKeepOpened keeps an opened gate opened after a change of its spec, until its new spec closes it.
+	GateSpecChangePolicyKeepOpened GateSpecChangePolicy = "KeepOpened"
+	// GateSpecChangePolicyReconsolidate requires an opened gate to meet its consolidation again after a change of its
+	// spec, as a closed gate.
+	GateSpecChangePolicyReconsolidate GateSpecChangePolicy = "Reconsolidate"
+)
+
+type GateTargetOnError = string
+
+const (
+	// GateTargetOnErrorFalse evaluates the target to false when its objects can't be fetched.
+	GateTargetOnErrorFalse GateTargetOnError = "False"
+	// GateTargetOnErrorTrue evaluates the target to true when its objects can't be fetched.
+	GateTargetOnErrorTrue GateTargetOnError = "True"
+	// GateTargetOnErrorKeepLastResult keeps the last result of the target when its objects can't be fetched, up to its
+	// max staleness.
+	GateTargetOnErrorKeepLastResult GateTargetOnError = "KeepLastResult"
+	// GateTargetOnErrorUnknown evaluates the target to unknown when its objects can't be fetched.
+	GateTargetOnErrorUnknown GateTargetOnError = "Unknown"
+)
+
+// GateConditionSuspended is present while the evaluation of the gate is suspended by its spec.
+const GateConditionSuspended = "Suspended"
+
+// GateConditionTimedOut is present while the gate is failed for not opening before its timeout.
+const GateConditionTimedOut = "TimedOut"
+
+// GateConditionFlapping is present while the gate transitions too often, with spec.flapping.
+const GateConditionFlapping = "Flapping"
+
+// Conditions following the kstatus conventions, read by the GitOps tools waiting for the gate to be ready.
+const (
+	// GateConditionReady is True while the gate is opened.
+	GateConditionReady = "Ready"
+	// GateConditionReconciling is present while the gate waits for its targets to be validated.
+	GateConditionReconciling = "Reconciling"
+	// GateConditionStalled is present while the objects of a target cannot be fetched.
+	GateConditionStalled = "Stalled"
+)
+
+// GateProtectFinalizer is set on the objects protected by a gate, and on the gate itself while it protects objects.
+const GateProtectFinalizer = "gate.sh/protect"
+
+// GateExportFinalizer is set on the gates exporting their state, to remove it from the ConfigMap upon deletion.
+const GateExportFinalizer = "gate.sh/export"
+
+// GateWaitForAnnotation lists the gates an object waits for, separated by commas. Each entry is either "name" or
+// "namespace/name" for a Gate, optionally prefixed by "Gate/", or "ClusterGate/name" for a ClusterGate.
+const GateWaitForAnnotation = "gate.sh/wait-for"
+
+// GateDeletionPolicyAnnotation defines what happens when a gate with dependents is deleted.
+const GateDeletionPolicyAnnotation = "gate.sh/deletion-policy"
+
+// GateResetLatchAnnotation resets a latched gate each time its value changes, for instance to the current time.
+const GateResetLatchAnnotation = "gate.sh/reset-latch"
+
+// GateReevaluateAnnotation triggers an immediate evaluation of the gate each time its value changes, even if its
+// evaluation is stopped by its timeout.
+const GateReevaluateAnnotation = "gate.sh/reevaluate"
+
+// GateForceStateAnnotation forces the state of the gate, Opened or Closed, without evaluating it until it's removed.
+const GateForceStateAnnotation = "gate.sh/force-state"
+
+// GateForcedByAnnotation records the user who forced the state of the gate. It's set by the mutating webhook.
+const GateForcedByAnnotation = "gate.sh/forced-by"
+
+// GateDebugAnnotation enables the verbose logs of a gate: "true" logs the evaluation details, "status" also writes
+// them to the status of the gate.
+const GateDebugAnnotation = "gate.sh/debug"
+
+const (
+	GateDebugLogs   = "true"
+	GateDebugStatus = "status"
+)
+
+// GateStatusDebugMaxLength is the maximum length of the debug trace kept in the status of the gate.
+const GateStatusDebugMaxLength = 8192
+
+type GateDeletionPolicy = string
+
+const (
+	GateDeletionPolicyWarn GateDeletionPolicy = "Warn"
+	GateDeletionPolicyDeny GateDeletionPolicy = "Deny"
 )
 
 type GateOperation struct {
@@ -133,16 +233,380 @@ type GateTarget struct {
 	// object was found by the selector regardless of its state.
 	// +optional
 	Validators []GateTargetValidator `json:"validators,omitempty,omitzero"`
+
+	// Defines the result of the target when its objects can't be fetched: False, True, KeepLastResult or Unknown. An
+	// unknown target makes the gate keep its state, unless the other targets decide its result. By default, False.
+	// +kubebuilder:validation:Enum=False;True;KeepLastResult;Unknown
+	// +optional
+	OnError GateTargetOnError `json:"onError,omitempty"`
+
+	// Maximum age of the last result kept with onError KeepLastResult, beyond which the target is false. By default,
+	// 5m.
+	// +optional
+	MaxStaleness *metav1.Duration `json:"maxStaleness,omitempty"`
 }
 
-// GateConsolidation defines the number of consecutive valid evaluation to consider the gate opened.
+// GateConsolidation defines the number of consecutive valid evaluations to open the gate.
 type GateConsolidation struct {
-	// Number of consecutive checks to consider the gate opened.
+	// Number of consecutive checks to open the gate.
 	Count int `json:"count,omitempty"`
 
 	// Delay between two checks. By default, it's 10s.
 	// +optional
 	Delay *metav1.Duration `json:"delay,omitempty"`
+
+	// Duration the condition must be continuously true to open the gate, in addition to the count.
+	// +optional
+	StableFor *metav1.Duration `json:"stableFor,omitempty"`
+}
+
+// GateCloseConsolidation defines the number of consecutive invalid evaluations to close the gate.
+type GateCloseConsolidation struct {
+	// Number of consecutive checks to close the gate.
+	Count int `json:"count,omitempty"`
+
+	// Delay between two checks. By default, it's 10s.
+	// +optional
+	Delay *metav1.Duration `json:"delay,omitempty"`
+}
+
+type GateFlapping struct {
+	// Number of transitions within the window above which the gate is flapping.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Threshold int `json:"threshold"`
+
+	// Sliding window the transitions are counted in. By default, 10m.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// Duration without transitions after which the gate is not flapping anymore. By default, the window.
+	// +optional
+	QuietPeriod *metav1.Duration `json:"quietPeriod,omitempty"`
+
+	// Holds the last stable state of the gate while it's flapping, instead of following its evaluations.
+	// +optional
+	Hold bool `json:"hold,omitempty"`
+}
+
+type GateActionPatchType = string
+
+const (
+	GateActionPatchTypeMerge     GateActionPatchType = "Merge"
+	GateActionPatchTypeStrategic GateActionPatchType = "Strategic"
+)
+
+type GateActionTrigger = string
+
+const (
+	GateActionTriggerOnOpen  GateActionTrigger = "OnOpen"
+	GateActionTriggerOnClose GateActionTrigger = "OnClose"
+)
+
+type GateActionResult = string
+
+const (
+	GateActionResultPending   GateActionResult = "Pending"
+	GateActionResultSucceeded GateActionResult = "Succeeded"
+	GateActionResultFailed    GateActionResult = "Failed"
+)
+
+// GateActionTarget references the object patched by an action.
+type GateActionTarget struct {
+	// Kind of the resource to patch
+	// +required
+	Kind string `json:"kind"`
+
+	// ApiVersion of the resource to patch
+	// +required
+	ApiVersion string `json:"apiVersion"`
+
+	// Namespace of the resource to patch. By default, the namespace of the gate if relevant.
+	// +optional
+	Namespace string `json:"namespace,omitempty,omitzero"`
+
+	// Name of the resource to patch
+	// +required
+	Name string `json:"name"`
+}
+
+// GateAction defines a patch applied to an object when the gate changes state.
+type GateAction struct {
+	// Name of the action. Must be PascalCase. Name will be inferred if not specified.
+	// +optional
+	Name string `json:"name"`
+
+	// Object to patch
+	// +required
+	Target GateActionTarget `json:"target"`
+
+	// Type of the patch. By default, "Merge" (JSON merge patch).
+	// +kubebuilder:validation:Enum=Merge;Strategic
+	// +optional
+	PatchType GateActionPatchType `json:"patchType,omitempty"`
+
+	// Patch to apply to the target object
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +required
+	Patch apiextensionsv1.JSON `json:"patch"`
+}
+
+// GateActions defines the actions executed once per gate transition.
+type GateActions struct {
+	// Actions executed when the gate opens
+	// +optional
+	OnOpen []GateAction `json:"onOpen,omitempty"`
+
+	// Actions executed when the gate closes
+	// +optional
+	OnClose []GateAction `json:"onClose,omitempty"`
+}
+
+// GateActionStatus records the execution of an action for the last transition.
+type GateActionStatus struct {
+	// Name of the action
+	Name string `json:"name"`
+
+	// Transition that triggered the action
+	Trigger GateActionTrigger `json:"trigger"`
+
+	// Result of the last attempt
+	Result GateActionResult `json:"result"`
+
+	// Number of attempts made for the current transition
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+
+	// Time of the transition that triggered the action
+	TransitionTime metav1.Time `json:"transitionTime"`
+
+	// Time of the last attempt
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// Details about the last attempt
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type GateNotificationResult = string
+
+const (
+	GateNotificationResultPending   GateNotificationResult = "Pending"
+	GateNotificationResultSucceeded GateNotificationResult = "Succeeded"
+	GateNotificationResultFailed    GateNotificationResult = "Failed"
+)
+
+// GateNotifications defines the channels notified when the gate changes state.
+type GateNotifications struct {
+	// Names of the NotificationChannels to notify
+	// +required
+	Channels []string `json:"channels"`
+
+	// States triggering a notification. By default, all the states.
+	// +optional
+	// +kubebuilder:validation:items:Enum=Opened;Closed;Failed
+	On []GateState `json:"on,omitempty"`
+}
+
+// GateNotificationStatus records the delivery of the notification of the last transition to a channel.
+type GateNotificationStatus struct {
+	// Name of the NotificationChannel
+	Channel string `json:"channel"`
+
+	// State the gate transitioned to
+	State GateState `json:"state"`
+
+	// State the gate transitioned from, empty for a gate created opened
+	// +optional
+	PreviousState GateState `json:"previousState,omitempty"`
+
+	// Result of the last delivery attempt
+	Result GateNotificationResult `json:"result"`
+
+	// Number of delivery attempts for the current transition
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+
+	// Time of the notified transition
+	TransitionTime metav1.Time `json:"transitionTime"`
+
+	// Time of the last delivery attempt
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// Details about the last delivery attempt
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GateExportConfigMap mirrors the state of the gate into a ConfigMap, under the key of the gate's name
+// (clustergate.<name> for ClusterGates).
+type GateExportConfigMap struct {
+	// Name of the ConfigMap. By default, gate-states, shared by the gates of the namespace.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the ConfigMap. Required for ClusterGates. Gates can only export in their own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// GateExport defines where the state of the gate is mirrored, for consumers not able to query the API.
+type GateExport struct {
+	// ConfigMap in which the state of the gate is written
+	// +optional
+	ConfigMap *GateExportConfigMap `json:"configMap,omitempty"`
+}
+
+// GateProtection defines objects whose deletion is held until the gate opens.
+type GateProtection struct {
+	// Selector of the objects to protect
+	// +required
+	Selector GateTargetSelector `json:"selector"`
+}
+
+// GateHistoryMaxEntries is the number of transitions kept in the history of a gate, the oldest being dropped first.
+const GateHistoryMaxEntries = 20
+
+// GateForcedState records the state forced by the gate.sh/force-state annotation.
+type GateForcedState struct {
+	// Forced state of the gate
+	State GateState `json:"state"`
+
+	// User who forced the state, recorded by the mutating webhook
+	// +optional
+	By string `json:"by,omitempty"`
+
+	// Time the state was forced
+	Time metav1.Time `json:"time"`
+}
+
+// GateFlappingStatus records the recent transitions of the gate to detect it flapping.
+type GateFlappingStatus struct {
+	// Recent transitions of the evaluated state of the gate, within the flapping window. Limited to threshold + 1
+	// entries.
+	// +optional
+	Transitions []GateFlappingTransition `json:"transitions,omitempty"`
+
+	// Time the gate started flapping
+	// +optional
+	Since *metav1.Time `json:"since,omitempty"`
+
+	// State held while the gate is flapping, with spec.flapping.hold
+	// +optional
+	HeldState GateState `json:"heldState,omitempty"`
+
+	// Last evaluated state of the gate, Opened or Closed, whatever its held state or its timeout
+	// +optional
+	EvaluatedState GateState `json:"evaluatedState,omitempty"`
+}
+
+// GateFlappingTransition records a transition counted by the flapping detection.
+type GateFlappingTransition struct {
+	// Time of the transition
+	Time metav1.Time `json:"time"`
+
+	// State of the gate before the transition
+	From GateState `json:"from"`
+}
+
+// GateHistoryEntry records a state transition of the gate.
+type GateHistoryEntry struct {
+	// Time of the transition
+	Time metav1.Time `json:"time"`
+
+	// State of the gate before the transition. Empty for the first evaluation of the gate.
+	// +optional
+	From GateState `json:"from,omitempty"`
+
+	// State of the gate after the transition
+	To GateState `json:"to"`
+
+	// Targets not validated at the time of the transition
+	// +optional
+	FailingTargets []string `json:"failingTargets,omitempty"`
+
+	// Reason of the transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// GateTargetFailingObjectsMaxEntries is the number of failing objects detailed in the status of a target.
+const GateTargetFailingObjectsMaxEntries = 10
+
+type GateValidatorType = string
+
+const (
+	GateValidatorMatchCondition GateValidatorType = "MatchCondition"
+	GateValidatorJsonPointer    GateValidatorType = "JsonPointer"
+)
+
+// GateTargetValidatorFailure explains why a validator rejected an object.
+type GateTargetValidatorFailure struct {
+	// Type of the validator
+	Validator GateValidatorType `json:"validator"`
+
+	// Reason of the failure
+	Message string `json:"message"`
+}
+
+// GateTargetFailingObject details an object rejected by the validators of a target.
+type GateTargetFailingObject struct {
+	// Namespace of the object
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object
+	Name string `json:"name"`
+
+	// Failures of the validators rejecting the object
+	Failures []GateTargetValidatorFailure `json:"failures"`
+}
+
+// GateTargetStatus details the result of the last evaluation of a target.
+type GateTargetStatus struct {
+	// Name of the target
+	Name string `json:"name"`
+
+	// Number of objects found by the selector
+	Found int `json:"found"`
+
+	// Number of objects validated by all the validators
+	Matched int `json:"matched"`
+
+	// Number of validated objects required to validate the target
+	Required int `json:"required"`
+
+	// Effective threshold, from which the required count is computed: All, AtLeast <count> or AtLeast <percent>%
+	// +optional
+	Threshold string `json:"threshold,omitempty"`
+
+	// First objects rejected by the validators, up to 10
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	FailingObjects []GateTargetFailingObject `json:"failingObjects,omitempty"`
+
+	// Time of the last result computed from the objects of the target
+	// +optional
+	LastResultTime *metav1.Time `json:"lastResultTime,omitempty"`
+}
+
+// GateObjectReference identifies an object by its kind, namespace and name.
+type GateObjectReference struct {
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// ApiVersion of the resource
+	ApiVersion string `json:"apiVersion"`
+
+	// Namespace of the resource
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource
+	Name string `json:"name"`
 }
 
 // GateSpec defines the desired state of Gate
@@ -168,6 +632,56 @@ type GateSpec struct {
 	// Defines the consolidation policy of a Gate. By default, at least 1 valid evaluation.
 	// +optional
 	Consolidation GateConsolidation `json:"consolidation,omitempty"`
+
+	// Defines the consolidation policy to close an opened Gate. By default, at least 1 invalid evaluation.
+	// +optional
+	CloseConsolidation GateCloseConsolidation `json:"closeConsolidation,omitempty"`
+
+	// Duration the gate has to open, from its creation or the last change of its spec. Once exceeded, the gate
+	// fails until it opens or its spec changes.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Defines the evaluation of a failed gate: Stop evaluating it, or Slow down its evaluation to the evaluation
+	// period. By default, Stop.
+	// +kubebuilder:validation:Enum=Stop;Slow
+	// +optional
+	TimeoutPolicy GateTimeoutPolicy `json:"timeoutPolicy,omitempty"`
+
+	// Defines whether an opened gate has to meet its consolidation again after a change of its spec: KeepOpened it,
+	// or Reconsolidate it. The consolidation counters restart on each change of the spec. By default, KeepOpened.
+	// +kubebuilder:validation:Enum=KeepOpened;Reconsolidate
+	// +optional
+	SpecChangePolicy GateSpecChangePolicy `json:"specChangePolicy,omitempty"`
+
+	// Detects the gate transitioning too often, and optionally holds its last stable state meanwhile.
+	// +optional
+	Flapping *GateFlapping `json:"flapping,omitempty"`
+
+	// Freezes the state of the gate and skips its evaluations.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Keeps the gate opened after its first opening, without evaluating its targets anymore, until it's reset by the
+	// gate.sh/reset-latch annotation.
+	// +optional
+	Latch bool `json:"latch,omitempty"`
+
+	// Defines the patches to apply to other objects when the gate opens or closes.
+	// +optional
+	Actions GateActions `json:"actions,omitempty,omitzero"`
+
+	// Objects on which the gate sets a finalizer, only released once they are being deleted and the gate is opened.
+	// +optional
+	Protect []GateProtection `json:"protect,omitempty"`
+
+	// Defines the notifications sent when the gate opens or closes.
+	// +optional
+	Notifications GateNotifications `json:"notifications,omitempty,omitzero"`
+
+	// Defines where the state of the gate is mirrored.
+	// +optional
+	Export GateExport `json:"export,omitempty,omitzero"`
 }
 
 // GateStatus defines the observed state of Gate.
@@ -196,6 +710,12 @@ type GateStatus struct {
 	// +optional
 	TargetConditions []metav1.Condition `json:"targetConditions,omitempty"`
 
+	// Detailed results of the targets
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Targets []GateTargetStatus `json:"targets,omitempty"`
+
 	// Easy access field representing the gate's condition
 	// +optional
 	State string `json:"state,omitempty"`
@@ -203,6 +723,89 @@ type GateStatus struct {
 	// Current consecutive valid checks
 	// +optional
 	ConsecutiveValidEvaluations int `json:"consecutiveValidEvaluations,omitempty"`
+
+	// Current consecutive invalid checks
+	// +optional
+	ConsecutiveInvalidEvaluations int `json:"consecutiveInvalidEvaluations,omitempty"`
+
+	// Time since the condition of the gate is continuously true
+	// +optional
+	ConditionTrueSince *metav1.Time `json:"conditionTrueSince,omitempty"`
+
+	// Time the timeout of the gate started from, its creation or the last change of its spec
+	// +optional
+	TimeoutStartTime *metav1.Time `json:"timeoutStartTime,omitempty"`
+
+	// Time the gate latched opened
+	// +optional
+	LatchedAt *metav1.Time `json:"latchedAt,omitempty"`
+
+	// Last value of the gate.sh/reset-latch annotation handled
+	// +optional
+	LastHandledLatchReset string `json:"lastHandledLatchReset,omitempty"`
+
+	// Last value of the gate.sh/reevaluate annotation handled
+	// +optional
+	LastHandledReevaluate string `json:"lastHandledReevaluate,omitempty"`
+
+	// State forced by the gate.sh/force-state annotation
+	// +optional
+	Forced *GateForcedState `json:"forced,omitempty"`
+
+	// Recent transitions of the gate and its flapping, with spec.flapping
+	// +optional
+	Flapping *GateFlappingStatus `json:"flapping,omitempty"`
+
+	// Results of the actions triggered by the last transition
+	// +optional
+	Actions []GateActionStatus `json:"actions,omitempty"`
+
+	// Objects currently holding the protect finalizer of the gate
+	// +optional
+	ProtectedObjects []GateObjectReference `json:"protectedObjects,omitempty"`
+
+	// Delivery results of the notifications of the last transition
+	// +optional
+	Notifications []GateNotificationStatus `json:"notifications,omitempty"`
+
+	// Sequence number of the last notified transition, identifying its notifications
+	// +optional
+	NotificationSequence int64 `json:"notificationSequence,omitempty"`
+
+	// ConfigMap currently holding the exported state of the gate
+	// +optional
+	ExportedConfigMap *GateObjectReference `json:"exportedConfigMap,omitempty"`
+
+	// Last transitions of the gate, the most recent last
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	History []GateHistoryEntry `json:"history,omitempty"`
+
+	// Time the gate opened for the first time
+	// +optional
+	FirstOpenedTime *metav1.Time `json:"firstOpenedTime,omitempty"`
+
+	// Time the gate opened for the last time
+	// +optional
+	LastOpenedTime *metav1.Time `json:"lastOpenedTime,omitempty"`
+
+	// Time of the last evaluation of the gate
+	// +optional
+	LastEvaluationTime *metav1.Time `json:"lastEvaluationTime,omitempty"`
+
+	// Generation of the spec observed by the last reconciliation
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Generation of the spec evaluated by the last evaluation
+	// +optional
+	LastEvaluatedGeneration int64 `json:"lastEvaluatedGeneration,omitempty"`
+
+	// Details of the last evaluation, written when the gate.sh/debug annotation is "status". Truncated to 8192
+	// characters.
+	// +kubebuilder:validation:MaxLength=8192
+	// +optional
+	Debug string `json:"debug,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -210,6 +813,9 @@ type GateStatus struct {
 
 // Gate is the Schema for the gates API
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="First Opened",type="date",JSONPath=`.status.firstOpenedTime`
+// +kubebuilder:printcolumn:name="Last Opened",type="date",JSONPath=`.status.lastOpenedTime`
+// +kubebuilder:printcolumn:name="Last Evaluation",type="date",JSONPath=`.status.lastEvaluationTime`
 type Gate struct {
 	metav1.TypeMeta `json:",inline"`
 
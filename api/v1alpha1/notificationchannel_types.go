@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NotificationSecretKeyReference selects a key of a Secret.
+type NotificationSecretKeyReference struct {
+	// Namespace of the secret
+	// +required
+	Namespace string `json:"namespace"`
+
+	// Name of the secret
+	// +required
+	Name string `json:"name"`
+
+	// Key of the secret holding the value
+	// +required
+	Key string `json:"key"`
+}
+
+// NotificationEndpoint defines where the notifications are sent. One of url or urlSecretRef must be provided.
+type NotificationEndpoint struct {
+	// URL of the endpoint
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Secret key holding the URL of the endpoint, for URLs embedding credentials
+	// +optional
+	URLSecretRef *NotificationSecretKeyReference `json:"urlSecretRef,omitempty"`
+}
+
+// NotificationChannelWebhook sends a JSON body built from a template to an HTTP endpoint.
+type NotificationChannelWebhook struct {
+	NotificationEndpoint `json:",inline"`
+
+	// Additional HTTP headers of the request
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Go template of the JSON body. The notification is available as the template data, and the json function
+	// encodes a value in JSON. By default, the notification is sent as JSON.
+	// +optional
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+}
+
+// NotificationChannelSlack sends a message to a Slack-compatible incoming webhook.
+type NotificationChannelSlack struct {
+	NotificationEndpoint `json:",inline"`
+
+	// Channel overriding the default channel of the incoming webhook
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Username overriding the default username of the incoming webhook
+	// +optional
+	Username string `json:"username,omitempty"`
+}
+
+// NotificationChannelAlertmanager sends alerts to the Alertmanager v2 API. A closed gate fires an alert, resolved
+// once the gate opens.
+type NotificationChannelAlertmanager struct {
+	NotificationEndpoint `json:",inline"`
+
+	// Additional labels of the alerts
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Duration after which a fired alert resolves itself if the gate didn't open. By default, 24h.
+	// +optional
+	AlertDuration *metav1.Duration `json:"alertDuration,omitempty"`
+}
+
+// NotificationChannelSpec defines the desired state of NotificationChannel
+// +kubebuilder:validation:XValidation:rule="(has(self.webhook) ? 1 : 0) + (has(self.slack) ? 1 : 0) + (has(self.alertmanager) ? 1 : 0) == 1",message="The channel must have exactly one sink."
+type NotificationChannelSpec struct {
+	// Generic webhook sink
+	// +optional
+	Webhook *NotificationChannelWebhook `json:"webhook,omitempty"`
+
+	// Slack-compatible sink
+	// +optional
+	Slack *NotificationChannelSlack `json:"slack,omitempty"`
+
+	// Alertmanager sink
+	// +optional
+	Alertmanager *NotificationChannelAlertmanager `json:"alertmanager,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// NotificationChannel is the Schema for the notificationchannels API
+type NotificationChannel struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of NotificationChannel
+	// +required
+	Spec NotificationChannelSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotificationChannelList contains a list of NotificationChannel
+type NotificationChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationChannel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationChannel{}, &NotificationChannelList{})
+}
@@ -111,6 +111,107 @@ func (in *Gate) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateAction) DeepCopyInto(out *GateAction) {
+	*out = *in
+	out.Target = in.Target
+	in.Patch.DeepCopyInto(&out.Patch)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateAction.
+func (in *GateAction) DeepCopy() *GateAction {
+	if in == nil {
+		return nil
+	}
+	out := new(GateAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateActionStatus) DeepCopyInto(out *GateActionStatus) {
+	*out = *in
+	in.TransitionTime.DeepCopyInto(&out.TransitionTime)
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateActionStatus.
+func (in *GateActionStatus) DeepCopy() *GateActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GateActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateActionTarget) DeepCopyInto(out *GateActionTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateActionTarget.
+func (in *GateActionTarget) DeepCopy() *GateActionTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(GateActionTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateActions) DeepCopyInto(out *GateActions) {
+	*out = *in
+	if in.OnOpen != nil {
+		in, out := &in.OnOpen, &out.OnOpen
+		*out = make([]GateAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OnClose != nil {
+		in, out := &in.OnClose, &out.OnClose
+		*out = make([]GateAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateActions.
+func (in *GateActions) DeepCopy() *GateActions {
+	if in == nil {
+		return nil
+	}
+	out := new(GateActions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateCloseConsolidation) DeepCopyInto(out *GateCloseConsolidation) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateCloseConsolidation.
+func (in *GateCloseConsolidation) DeepCopy() *GateCloseConsolidation {
+	if in == nil {
+		return nil
+	}
+	out := new(GateCloseConsolidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GateConsolidation) DeepCopyInto(out *GateConsolidation) {
 	*out = *in
@@ -119,6 +220,11 @@ func (in *GateConsolidation) DeepCopyInto(out *GateConsolidation) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.StableFor != nil {
+		in, out := &in.StableFor, &out.StableFor
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateConsolidation.
@@ -131,6 +237,145 @@ func (in *GateConsolidation) DeepCopy() *GateConsolidation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateExport) DeepCopyInto(out *GateExport) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(GateExportConfigMap)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateExport.
+func (in *GateExport) DeepCopy() *GateExport {
+	if in == nil {
+		return nil
+	}
+	out := new(GateExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateExportConfigMap) DeepCopyInto(out *GateExportConfigMap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateExportConfigMap.
+func (in *GateExportConfigMap) DeepCopy() *GateExportConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(GateExportConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateFlapping) DeepCopyInto(out *GateFlapping) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.QuietPeriod != nil {
+		in, out := &in.QuietPeriod, &out.QuietPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateFlapping.
+func (in *GateFlapping) DeepCopy() *GateFlapping {
+	if in == nil {
+		return nil
+	}
+	out := new(GateFlapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateFlappingStatus) DeepCopyInto(out *GateFlappingStatus) {
+	*out = *in
+	if in.Transitions != nil {
+		in, out := &in.Transitions, &out.Transitions
+		*out = make([]GateFlappingTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateFlappingStatus.
+func (in *GateFlappingStatus) DeepCopy() *GateFlappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GateFlappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateFlappingTransition) DeepCopyInto(out *GateFlappingTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateFlappingTransition.
+func (in *GateFlappingTransition) DeepCopy() *GateFlappingTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(GateFlappingTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateForcedState) DeepCopyInto(out *GateForcedState) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateForcedState.
+func (in *GateForcedState) DeepCopy() *GateForcedState {
+	if in == nil {
+		return nil
+	}
+	out := new(GateForcedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateHistoryEntry) DeepCopyInto(out *GateHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.FailingTargets != nil {
+		in, out := &in.FailingTargets, &out.FailingTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateHistoryEntry.
+func (in *GateHistoryEntry) DeepCopy() *GateHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(GateHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GateList) DeepCopyInto(out *GateList) {
 	*out = *in
@@ -163,6 +408,66 @@ func (in *GateList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateNotificationStatus) DeepCopyInto(out *GateNotificationStatus) {
+	*out = *in
+	in.TransitionTime.DeepCopyInto(&out.TransitionTime)
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateNotificationStatus.
+func (in *GateNotificationStatus) DeepCopy() *GateNotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GateNotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateNotifications) DeepCopyInto(out *GateNotifications) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.On != nil {
+		in, out := &in.On, &out.On
+		*out = make([]GateState, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateNotifications.
+func (in *GateNotifications) DeepCopy() *GateNotifications {
+	if in == nil {
+		return nil
+	}
+	out := new(GateNotifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateObjectReference) DeepCopyInto(out *GateObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateObjectReference.
+func (in *GateObjectReference) DeepCopy() *GateObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GateObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GateOperation) DeepCopyInto(out *GateOperation) {
 	*out = *in
@@ -178,6 +483,22 @@ func (in *GateOperation) DeepCopy() *GateOperation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateProtection) DeepCopyInto(out *GateProtection) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateProtection.
+func (in *GateProtection) DeepCopy() *GateProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(GateProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GateSpec) DeepCopyInto(out *GateSpec) {
 	*out = *in
@@ -195,6 +516,27 @@ func (in *GateSpec) DeepCopyInto(out *GateSpec) {
 		**out = **in
 	}
 	in.Consolidation.DeepCopyInto(&out.Consolidation)
+	in.CloseConsolidation.DeepCopyInto(&out.CloseConsolidation)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Flapping != nil {
+		in, out := &in.Flapping, &out.Flapping
+		*out = new(GateFlapping)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Actions.DeepCopyInto(&out.Actions)
+	if in.Protect != nil {
+		in, out := &in.Protect, &out.Protect
+		*out = make([]GateProtection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Notifications.DeepCopyInto(&out.Notifications)
+	in.Export.DeepCopyInto(&out.Export)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateSpec.
@@ -224,6 +566,78 @@ func (in *GateStatus) DeepCopyInto(out *GateStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]GateTargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConditionTrueSince != nil {
+		in, out := &in.ConditionTrueSince, &out.ConditionTrueSince
+		*out = (*in).DeepCopy()
+	}
+	if in.TimeoutStartTime != nil {
+		in, out := &in.TimeoutStartTime, &out.TimeoutStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LatchedAt != nil {
+		in, out := &in.LatchedAt, &out.LatchedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Forced != nil {
+		in, out := &in.Forced, &out.Forced
+		*out = new(GateForcedState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Flapping != nil {
+		in, out := &in.Flapping, &out.Flapping
+		*out = new(GateFlappingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]GateActionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProtectedObjects != nil {
+		in, out := &in.ProtectedObjects, &out.ProtectedObjects
+		*out = make([]GateObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]GateNotificationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExportedConfigMap != nil {
+		in, out := &in.ExportedConfigMap, &out.ExportedConfigMap
+		*out = new(GateObjectReference)
+		**out = **in
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]GateHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FirstOpenedTime != nil {
+		in, out := &in.FirstOpenedTime, &out.FirstOpenedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastOpenedTime != nil {
+		in, out := &in.LastOpenedTime, &out.LastOpenedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastEvaluationTime != nil {
+		in, out := &in.LastEvaluationTime, &out.LastEvaluationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateStatus.
@@ -245,6 +659,11 @@ func (in *GateTarget) DeepCopyInto(out *GateTarget) {
 		*out = make([]GateTargetValidator, len(*in))
 		copy(*out, *in)
 	}
+	if in.MaxStaleness != nil {
+		in, out := &in.MaxStaleness, &out.MaxStaleness
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateTarget.
@@ -257,6 +676,28 @@ func (in *GateTarget) DeepCopy() *GateTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateTargetFailingObject) DeepCopyInto(out *GateTargetFailingObject) {
+	*out = *in
+	if in.Failures != nil {
+		in, out := &in.Failures, &out.Failures
+		*out = make([]GateTargetValidatorFailure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateTargetFailingObject.
+func (in *GateTargetFailingObject) DeepCopy() *GateTargetFailingObject {
+	if in == nil {
+		return nil
+	}
+	out := new(GateTargetFailingObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GateTargetSelector) DeepCopyInto(out *GateTargetSelector) {
 	*out = *in
@@ -273,6 +714,32 @@ func (in *GateTargetSelector) DeepCopy() *GateTargetSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateTargetStatus) DeepCopyInto(out *GateTargetStatus) {
+	*out = *in
+	if in.FailingObjects != nil {
+		in, out := &in.FailingObjects, &out.FailingObjects
+		*out = make([]GateTargetFailingObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastResultTime != nil {
+		in, out := &in.LastResultTime, &out.LastResultTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateTargetStatus.
+func (in *GateTargetStatus) DeepCopy() *GateTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GateTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GateTargetValidator) DeepCopyInto(out *GateTargetValidator) {
 	*out = *in
@@ -306,6 +773,21 @@ func (in *GateTargetValidatorAtLeast) DeepCopy() *GateTargetValidatorAtLeast {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GateTargetValidatorFailure) DeepCopyInto(out *GateTargetValidatorFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GateTargetValidatorFailure.
+func (in *GateTargetValidatorFailure) DeepCopy() *GateTargetValidatorFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(GateTargetValidatorFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GateTargetValidatorJsonPointer) DeepCopyInto(out *GateTargetValidatorJsonPointer) {
 	*out = *in
@@ -335,3 +817,193 @@ func (in *GateTargetValidatorMatchCondition) DeepCopy() *GateTargetValidatorMatc
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannel) DeepCopyInto(out *NotificationChannel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannel.
+func (in *NotificationChannel) DeepCopy() *NotificationChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationChannel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelAlertmanager) DeepCopyInto(out *NotificationChannelAlertmanager) {
+	*out = *in
+	in.NotificationEndpoint.DeepCopyInto(&out.NotificationEndpoint)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AlertDuration != nil {
+		in, out := &in.AlertDuration, &out.AlertDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelAlertmanager.
+func (in *NotificationChannelAlertmanager) DeepCopy() *NotificationChannelAlertmanager {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelAlertmanager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelList) DeepCopyInto(out *NotificationChannelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NotificationChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelList.
+func (in *NotificationChannelList) DeepCopy() *NotificationChannelList {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationChannelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelSlack) DeepCopyInto(out *NotificationChannelSlack) {
+	*out = *in
+	in.NotificationEndpoint.DeepCopyInto(&out.NotificationEndpoint)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelSlack.
+func (in *NotificationChannelSlack) DeepCopy() *NotificationChannelSlack {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelSlack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelSpec) DeepCopyInto(out *NotificationChannelSpec) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(NotificationChannelWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(NotificationChannelSlack)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alertmanager != nil {
+		in, out := &in.Alertmanager, &out.Alertmanager
+		*out = new(NotificationChannelAlertmanager)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelSpec.
+func (in *NotificationChannelSpec) DeepCopy() *NotificationChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelWebhook) DeepCopyInto(out *NotificationChannelWebhook) {
+	*out = *in
+	in.NotificationEndpoint.DeepCopyInto(&out.NotificationEndpoint)
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelWebhook.
+func (in *NotificationChannelWebhook) DeepCopy() *NotificationChannelWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationEndpoint) DeepCopyInto(out *NotificationEndpoint) {
+	*out = *in
+	if in.URLSecretRef != nil {
+		in, out := &in.URLSecretRef, &out.URLSecretRef
+		*out = new(NotificationSecretKeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationEndpoint.
+func (in *NotificationEndpoint) DeepCopy() *NotificationEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSecretKeyReference) DeepCopyInto(out *NotificationSecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSecretKeyReference.
+func (in *NotificationSecretKeyReference) DeepCopy() *NotificationSecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
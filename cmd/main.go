@@ -17,9 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"io"
+	"net/http"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -36,7 +40,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/audit"
 	"github.com/robinlioret/gate-operator/internal/controller"
+	"github.com/robinlioret/gate-operator/internal/notifier"
+	"github.com/robinlioret/gate-operator/internal/readiness"
+	"github.com/robinlioret/gate-operator/internal/tracing"
 	webhookv1alpha1 "github.com/robinlioret/gate-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
@@ -62,6 +70,14 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var cloudEventsSinkURL, cloudEventsMode, cloudEventsSource string
+	var tracingOptions tracing.Options
+	var auditOutput, auditMode, auditGates string
+	var auditMaxSize int64
+	var auditMaxBackups int
+	var enableDebugEndpoint bool
+	var readinessAddr, readinessTokenFile, readinessClientCAFile string
+	var readinessCertPath, readinessCertName, readinessCertKey string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -80,6 +96,41 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&cloudEventsSinkURL, "cloudevents-sink-url", "",
+		"The URL the gate lifecycle CloudEvents are sent to. Leave empty to disable the CloudEvents.")
+	flag.StringVar(&cloudEventsMode, "cloudevents-mode", notifier.CloudEventModeBinary,
+		"The HTTP content mode of the CloudEvents: binary or structured.")
+	flag.StringVar(&cloudEventsSource, "cloudevents-source", "gate-operator", "The source attribute of the CloudEvents.")
+	flag.StringVar(&tracingOptions.Exporter, "tracing-exporter", tracing.ExporterNone,
+		"The exporter of the OpenTelemetry spans of the gate evaluations: none, otlp or stdout.")
+	flag.StringVar(&tracingOptions.Endpoint, "tracing-endpoint", "",
+		"The host:port of the OTLP HTTP collector. Defaults to the OTEL_EXPORTER_OTLP_* environment variables.")
+	flag.BoolVar(&tracingOptions.Insecure, "tracing-insecure", false,
+		"If set, the spans are sent to the OTLP collector without TLS.")
+	flag.Float64Var(&tracingOptions.SamplingRatio, "tracing-sampling-ratio", 1, "The ratio of the sampled traces.")
+	flag.StringVar(&auditOutput, "audit-output", "",
+		"Where the audit records are written: stdout or the path of a rotated file. Leave empty to disable the audit.")
+	flag.StringVar(&auditMode, "audit-mode", audit.ModeTransition,
+		"The evaluations audited: transition for the state transitions only, or evaluation for every evaluation.")
+	flag.StringVar(&auditGates, "audit-gates", "",
+		"Comma-separated patterns of the audited gates, namespace/name for Gates and name for ClusterGates. "+
+			"Leave empty to audit all the gates.")
+	flag.Int64Var(&auditMaxSize, "audit-max-size", 100, "The size in megabytes from which the audit file is rotated.")
+	flag.IntVar(&auditMaxBackups, "audit-max-backups", 5, "The number of rotated audit files kept.")
+	flag.BoolVar(&enableDebugEndpoint, "enable-debug-endpoint", false,
+		"If set, the last evaluation of the gates is served under /debug on the metrics server. "+
+			"Requires --metrics-secure to authenticate and authorize the requests.")
+	flag.StringVar(&readinessAddr, "readiness-bind-address", "",
+		"The address the read-only gate readiness API binds to. Leave empty to disable it.")
+	flag.StringVar(&readinessTokenFile, "readiness-token-file", "",
+		"The file holding the bearer tokens accepted by the readiness API, one per line, optionally followed by the "+
+			"comma-separated namespaces the token is restricted to. Requires --readiness-cert-path.")
+	flag.StringVar(&readinessCertPath, "readiness-cert-path", "",
+		"The directory that contains the readiness API certificate. The API is served over HTTP if empty.")
+	flag.StringVar(&readinessCertName, "readiness-cert-name", "tls.crt", "The name of the readiness API certificate file.")
+	flag.StringVar(&readinessCertKey, "readiness-cert-key", "tls.key", "The name of the readiness API key file.")
+	flag.StringVar(&readinessClientCAFile, "readiness-client-ca-file", "",
+		"The CA bundle verifying the client certificates of the readiness API. Client certificates are not required if empty.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -158,6 +209,23 @@ func main() {
 		metricsServerOptions.KeyName = metricsCertKey
 	}
 
+	// The debug endpoint is served by the metrics server, behind its authentication and authorization filter.
+	var debugStore *controller.GateDebugStore
+	var debugHandler *controller.GateDebugHandler
+	if enableDebugEndpoint {
+		if !secureMetrics {
+			setupLog.Error(nil, "the debug endpoint requires --metrics-secure")
+			os.Exit(1)
+		}
+		debugStore = controller.NewGateDebugStore()
+		debugHandler = &controller.GateDebugHandler{Store: debugStore}
+		metricsServerOptions.ExtraHandlers = map[string]http.Handler{
+			controller.DebugGatesPath:              debugHandler,
+			controller.DebugGatesPath + "/":        debugHandler,
+			controller.DebugClusterGatesPath + "/": debugHandler,
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
@@ -182,9 +250,62 @@ func main() {
 		os.Exit(1)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	if debugHandler != nil {
+		debugHandler.Client = mgr.GetClient()
+		debugHandler.Elected = mgr.Elected()
+	}
+
+	gateNotifier := notifier.NewGateNotifier(mgr.GetAPIReader())
+	var cloudEventEmitter *notifier.CloudEventEmitter
+	if cloudEventsSinkURL != "" {
+		cloudEventEmitter, err = notifier.NewCloudEventEmitter(cloudEventsSinkURL, cloudEventsMode, cloudEventsSource)
+		if err != nil {
+			setupLog.Error(err, "unable to create CloudEvents emitter")
+			os.Exit(1)
+		}
+		if err := mgr.Add(cloudEventEmitter); err != nil {
+			setupLog.Error(err, "unable to add CloudEvents emitter to the manager")
+			os.Exit(1)
+		}
+	}
+	var auditor *audit.Auditor
+	if auditOutput != "" {
+		var auditWriter io.Writer = os.Stdout
+		if auditOutput != "stdout" {
+			auditFile, err := audit.NewRotatingFile(auditOutput, auditMaxSize*1024*1024, auditMaxBackups)
+			if err != nil {
+				setupLog.Error(err, "unable to open audit file")
+				os.Exit(1)
+			}
+			defer auditFile.Close() //nolint:errcheck
+			auditWriter = auditFile
+		}
+		var auditGatePatterns []string
+		if auditGates != "" {
+			auditGatePatterns = strings.Split(auditGates, ",")
+		}
+		auditor, err = audit.NewAuditor(auditWriter, audit.Options{Mode: auditMode, Gates: auditGatePatterns})
+		if err != nil {
+			setupLog.Error(err, "unable to create auditor")
+			os.Exit(1)
+		}
+	}
 	if err := (&controller.GateReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		GateOutputs: controller.GateOutputs{
+			Notifier:    gateNotifier,
+			CloudEvents: cloudEventEmitter,
+			Recorder:    controller.NewGateEventRecorder(mgr.GetEventRecorderFor("gate-controller")),
+			Auditor:     auditor,
+			Debug:       debugStore,
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Gate")
 		os.Exit(1)
@@ -192,12 +313,23 @@ func main() {
 	if err := (&controller.ClusterGateReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		GateOutputs: controller.GateOutputs{
+			Notifier:    gateNotifier,
+			CloudEvents: cloudEventEmitter,
+			Recorder:    controller.NewGateEventRecorder(mgr.GetEventRecorderFor("clustergate-controller")),
+			Auditor:     auditor,
+			Debug:       debugStore,
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterGate")
 		os.Exit(1)
 	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1alpha1.SetupWaitForIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+			setupLog.Error(err, "unable to create the wait-for indexes")
+			os.Exit(1)
+		}
 		if err := webhookv1alpha1.SetupGateWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Gate")
 			os.Exit(1)
@@ -212,6 +344,40 @@ func main() {
 	}
 	// +kubebuilder:scaffold:builder
 
+	if readinessAddr != "" {
+		var readinessTokens []readiness.Token
+		if readinessTokenFile != "" {
+			content, err := os.ReadFile(readinessTokenFile)
+			if err != nil {
+				setupLog.Error(err, "unable to read the readiness tokens")
+				os.Exit(1)
+			}
+			readinessTokens = readiness.ParseTokens(string(content))
+		}
+		readinessServer, err := readiness.NewServer(mgr.GetClient(), readiness.Options{
+			BindAddress:  readinessAddr,
+			Tokens:       readinessTokens,
+			CertDir:      readinessCertPath,
+			CertName:     readinessCertName,
+			KeyName:      readinessCertKey,
+			ClientCAFile: readinessClientCAFile,
+			TLSOpts:      tlsOpts,
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create the readiness server")
+			os.Exit(1)
+		}
+		readinessServer.Watch = readiness.NewBroadcaster()
+		if err := readinessServer.Watch.SetupWithCache(context.Background(), mgr.GetCache()); err != nil {
+			setupLog.Error(err, "unable to set up the readiness watch")
+			os.Exit(1)
+		}
+		if err := mgr.Add(readinessServer); err != nil {
+			setupLog.Error(err, "unable to set up the readiness server")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -226,4 +392,7 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "unable to flush the spans")
+	}
 }
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit writes the JSON audit records of the gate evaluations.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Mode = string
+
+const (
+	// ModeEvaluation writes a record for each evaluation of the gates.
+	ModeEvaluation Mode = "evaluation"
+	// ModeTransition writes a record for the state transitions of the gates only.
+	ModeTransition Mode = "transition"
+)
+
+// GateReference identifies the audited gate.
+type GateReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// ObjectRecord identifies an evaluated object and the version it was evaluated at.
+type ObjectRecord struct {
+	ApiVersion      string `json:"apiVersion"`
+	Kind            string `json:"kind"`
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name"`
+	UID             string `json:"uid,omitempty"`
+	ResourceVersion string `json:"resourceVersion"`
+	// Valid is true if the object was validated by all the validators of the target.
+	Valid bool `json:"valid"`
+}
+
+// TargetRecord is the result of the evaluation of a target.
+type TargetRecord struct {
+	Name    string                 `json:"name"`
+	Status  metav1.ConditionStatus `json:"status"`
+	Reason  string                 `json:"reason"`
+	Message string                 `json:"message,omitempty"`
+	Objects []ObjectRecord         `json:"objects"`
+}
+
+// Record is the audit record of an evaluation of a gate.
+type Record struct {
+	Time          time.Time      `json:"time"`
+	Gate          GateReference  `json:"gate"`
+	Generation    int64          `json:"generation"`
+	Transition    bool           `json:"transition"`
+	PreviousState string         `json:"previousState,omitempty"`
+	State         string         `json:"state"`
+	ForcedBy      string         `json:"forcedBy,omitempty"`
+	Targets       []TargetRecord `json:"targets"`
+}
+
+// Options configures the audited evaluations.
+type Options struct {
+	Mode Mode
+	// Gates are the patterns of the audited gates, matched against namespace/name for Gates and name for
+	// ClusterGates. All the gates are audited if empty.
+	Gates []string
+}
+
+// Auditor writes the audit records as JSON lines.
+type Auditor struct {
+	writer  io.Writer
+	options Options
+	mutex   sync.Mutex
+}
+
+func NewAuditor(writer io.Writer, options Options) (*Auditor, error) {
+	switch options.Mode {
+	case ModeEvaluation, ModeTransition:
+	default:
+		return nil, fmt.Errorf("invalid audit mode %s, must be %s or %s", options.Mode, ModeEvaluation, ModeTransition)
+	}
+	for _, pattern := range options.Gates {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid audit gate pattern %s: %w", pattern, err)
+		}
+	}
+	return &Auditor{writer: writer, options: options}, nil
+}
+
+// Accepts returns true if an evaluation of the gate must be audited.
+func (a *Auditor) Accepts(gate GateReference, transition bool) bool {
+	if a.options.Mode == ModeTransition && !transition {
+		return false
+	}
+	if len(a.options.Gates) == 0 {
+		return true
+	}
+	key := gate.Name
+	if gate.Namespace != "" {
+		key = fmt.Sprintf("%s/%s", gate.Namespace, gate.Name)
+	}
+	for _, pattern := range a.options.Gates {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the record as a single JSON line.
+func (a *Auditor) Write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	_, err = a.writer.Write(append(line, '\n'))
+	return err
+}
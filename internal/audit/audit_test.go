@@ -0,0 +1,94 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auditor", func() {
+	gate := GateReference{Kind: "Gate", Namespace: "production", Name: "release"}
+	clusterGate := GateReference{Kind: "ClusterGate", Name: "platform"}
+
+	It("should reject an invalid configuration", func() {
+		_, err := NewAuditor(&bytes.Buffer{}, Options{Mode: "always"})
+		Expect(err).To(HaveOccurred())
+		_, err = NewAuditor(&bytes.Buffer{}, Options{Mode: ModeTransition, Gates: []string{"["}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only accept the transitions in transition mode", func() {
+		auditor, err := NewAuditor(&bytes.Buffer{}, Options{Mode: ModeTransition})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auditor.Accepts(gate, true)).To(BeTrue())
+		Expect(auditor.Accepts(gate, false)).To(BeFalse())
+
+		auditor, err = NewAuditor(&bytes.Buffer{}, Options{Mode: ModeEvaluation})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auditor.Accepts(gate, false)).To(BeTrue())
+	})
+
+	It("should only accept the selected gates", func() {
+		auditor, err := NewAuditor(&bytes.Buffer{}, Options{Mode: ModeEvaluation, Gates: []string{"production/*", "plat*"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auditor.Accepts(gate, false)).To(BeTrue())
+		Expect(auditor.Accepts(clusterGate, false)).To(BeTrue())
+		Expect(auditor.Accepts(GateReference{Kind: "Gate", Namespace: "staging", Name: "release"}, true)).To(BeFalse())
+	})
+
+	It("should write one JSON line per record", func() {
+		buffer := &bytes.Buffer{}
+		auditor, err := NewAuditor(buffer, Options{Mode: ModeEvaluation})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(auditor.Write(Record{Gate: gate, State: "Opened", Targets: []TargetRecord{{Name: "Config"}}})).To(Succeed())
+		Expect(auditor.Write(Record{Gate: gate, State: "Closed"})).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+		var record Record
+		Expect(json.Unmarshal([]byte(lines[0]), &record)).To(Succeed())
+		Expect(record.Gate).To(Equal(gate))
+		Expect(record.Targets[0].Name).To(Equal("Config"))
+	})
+})
+
+var _ = Describe("RotatingFile", func() {
+	It("should rotate the file once it reaches its maximum size", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "audit.log")
+		file, err := NewRotatingFile(path, 10, 2)
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close() //nolint:errcheck
+
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err := file.Write([]byte(line))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(os.ReadFile(path)).To(Equal([]byte("fourth\n")))
+		Expect(os.ReadFile(path + ".1")).To(Equal([]byte("third\n")))
+		Expect(os.ReadFile(path + ".2")).To(Equal([]byte("second\n")))
+		Expect(path + ".3").NotTo(BeAnExistingFile())
+	})
+})
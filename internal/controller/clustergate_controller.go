@@ -23,16 +23,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/metrics"
 )
 
 // ClusterGateReconciler reconciles a ClusterGate object
 type ClusterGateReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	GateOutputs
 }
 
 // +kubebuilder:rbac:groups=gate.sh,resources=clustergates,verbs=get;list;watch;create;update;patch;delete
@@ -56,25 +59,29 @@ func (r *ClusterGateReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	err := r.Get(ctx, req.NamespacedName, &gate)
 	if errors.IsNotFound(err) {
 		log.Info("ClusterGate not found")
+		metrics.DeleteGate(metrics.GateLabels{Kind: "ClusterGate", Namespace: req.Namespace, Name: req.Name})
+		if r.Debug != nil {
+			r.Debug.Delete("ClusterGate", req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to fetch ClusterGate")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	gateObject := gateshv1alpha1.Gate{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "ClusterGate",
-			APIVersion: gateshv1alpha1.GroupVersion.String(),
-		},
-		ObjectMeta: gate.ObjectMeta,
-		Spec:       gate.Spec,
-		Status:     gate.Status,
+	if AddGateFinalizers(&gate, &gate.Spec) {
+		if err := r.Update(ctx, &gate); err != nil {
+			log.Error(err, "unable to add finalizers to ClusterGate")
+			return ctrl.Result{}, err
+		}
 	}
+
+	gateObject := ClusterGateAsGate(&gate)
 	gcr := GateCommonReconciler{
-		Context: ctx,
-		Client:  r.Client,
-		Gate:    &gateObject,
+		Context:     ctx,
+		Client:      r.Client,
+		GateOutputs: r.GateOutputs,
+		Gate:        &gateObject,
 	}
 	err = gcr.Reconcile()
 	gate.Status = gateObject.Status
@@ -84,14 +91,36 @@ func (r *ClusterGateReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 	if err := r.Status().Update(ctx, &gate); err != nil {
 		log.Error(err, "unable to update ClusterGate")
+		return ctrl.Result{}, err
+	}
+	// Once the protected objects are released and the exported state removed, the gate can be deleted.
+	if gcr.RemoveGateFinalizers(&gate) {
+		if err := r.Update(ctx, &gate); err != nil {
+			log.Error(err, "unable to remove finalizers from ClusterGate")
+			return ctrl.Result{RequeueAfter: gcr.RequeueAfter}, err
+		}
 	}
 	return ctrl.Result{RequeueAfter: gcr.RequeueAfter}, err
 }
 
+// ClusterGateAsGate converts a ClusterGate into the Gate reconciled by the GateCommonReconciler, keeping the
+// ClusterGate kind.
+func ClusterGateAsGate(gate *gateshv1alpha1.ClusterGate) gateshv1alpha1.Gate {
+	return gateshv1alpha1.Gate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterGate",
+			APIVersion: gateshv1alpha1.GroupVersion.String(),
+		},
+		ObjectMeta: gate.ObjectMeta,
+		Spec:       gate.Spec,
+		Status:     gate.Status,
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterGateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&gateshv1alpha1.ClusterGate{}).
+		For(&gateshv1alpha1.ClusterGate{}, builder.WithPredicates(StatusUpdateIgnoredPredicate())).
 		Named("clustergate").
 		Complete(r)
 }
@@ -105,6 +105,7 @@ var _ = Describe("ClusterGate Controller", func() {
 	client := fake.NewClientBuilder().
 		WithScheme(scheme).
 		WithObjects(clustergate1, cm1, deploy1).
+		WithStatusSubresource(clustergate1).
 		Build()
 
 	Context("When reconciling a non-existing resource", func() {
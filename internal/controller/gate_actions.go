@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// StartActions replaces the actions status with the actions triggered by the transition to the given state.
+// The actions are then executed by ExecuteActions until they succeed. The transitions to the Failed state have no
+// actions, and keep the actions of the previous transition.
+func (g *GateCommonReconciler) StartActions(state gateshv1alpha1.GateState) {
+	var actions []gateshv1alpha1.GateAction
+	var trigger gateshv1alpha1.GateActionTrigger
+	switch state {
+	case gateshv1alpha1.GateStateOpened:
+		actions = g.Gate.Spec.Actions.OnOpen
+		trigger = gateshv1alpha1.GateActionTriggerOnOpen
+	case gateshv1alpha1.GateStateClosed:
+		actions = g.Gate.Spec.Actions.OnClose
+		trigger = gateshv1alpha1.GateActionTriggerOnClose
+	default:
+		return
+	}
+
+	now := metav1.Now()
+	statuses := make([]gateshv1alpha1.GateActionStatus, 0, len(actions))
+	for _, action := range actions {
+		statuses = append(statuses, gateshv1alpha1.GateActionStatus{
+			Name:           action.Name,
+			Trigger:        trigger,
+			Result:         gateshv1alpha1.GateActionResultPending,
+			TransitionTime: now,
+		})
+	}
+	g.Gate.Status.Actions = statuses
+	g.ActionsStarted = len(statuses) > 0
+}
+
+// ExecuteActions runs the pending actions of the last transition. Failed actions are retried with an exponential
+// backoff, successful ones are never executed again for the same transition.
+func (g *GateCommonReconciler) ExecuteActions() {
+	log := logf.FromContext(g.Context)
+	if g.ActionsStarted {
+		// Executed once the status recording the transition is persisted, so that the transition can't be replayed
+		// from a stale status.
+		g.ShortenRequeueAfter(ActionsStartDelay)
+		return
+	}
+
+	for idx := range g.Gate.Status.Actions {
+		actionStatus := &g.Gate.Status.Actions[idx]
+		if actionStatus.Result == gateshv1alpha1.GateActionResultSucceeded {
+			continue
+		}
+
+		action := g.FindAction(actionStatus.Trigger, actionStatus.Name)
+		if action == nil {
+			actionStatus.Result = gateshv1alpha1.GateActionResultFailed
+			actionStatus.Message = "action not found in spec"
+			continue
+		}
+
+		if actionStatus.Result == gateshv1alpha1.GateActionResultFailed && actionStatus.LastAttemptTime != nil {
+			nextAttempt := actionStatus.LastAttemptTime.Add(g.GetRetryDelay(actionStatus.Attempts))
+			if wait := time.Until(nextAttempt); wait > 0 {
+				g.ShortenRequeueAfter(wait)
+				continue
+			}
+		}
+
+		err := g.ExecuteAction(action)
+		now := metav1.Now()
+		actionStatus.Attempts += 1
+		actionStatus.LastAttemptTime = &now
+		if err != nil {
+			log.Error(err, "unable to execute action", "action", action.Name, "trigger", actionStatus.Trigger)
+			actionStatus.Result = gateshv1alpha1.GateActionResultFailed
+			actionStatus.Message = err.Error()
+			g.ShortenRequeueAfter(g.GetRetryDelay(actionStatus.Attempts))
+			continue
+		}
+		actionStatus.Result = gateshv1alpha1.GateActionResultSucceeded
+		actionStatus.Message = fmt.Sprintf("%s patch applied to %s %s", action.PatchType, action.Target.Kind, action.Target.Name)
+	}
+}
+
+func (g *GateCommonReconciler) ExecuteAction(action *gateshv1alpha1.GateAction) error {
+	gv, err := schema.ParseGroupVersion(action.Target.ApiVersion)
+	if err != nil {
+		return fmt.Errorf("invalid ApiVersion %s: %w", action.Target.ApiVersion, err)
+	}
+
+	namespace := action.Target.Namespace
+	if namespace == "" {
+		namespace = g.Gate.Namespace
+	}
+	if g.Gate.Namespace != "" && namespace != g.Gate.Namespace {
+		return fmt.Errorf("a Gate can only patch objects in its own namespace %s", g.Gate.Namespace)
+	}
+
+	patchType := types.MergePatchType
+	if action.PatchType == gateshv1alpha1.GateActionPatchTypeStrategic {
+		patchType = types.StrategicMergePatchType
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gv.WithKind(action.Target.Kind))
+	obj.SetNamespace(namespace)
+	obj.SetName(action.Target.Name)
+	if err := g.Client.Patch(g.Context, obj, client.RawPatch(patchType, action.Patch.Raw)); err != nil {
+		return fmt.Errorf("failed to patch object %s/%s: %w", namespace, action.Target.Name, err)
+	}
+	return nil
+}
+
+func (g *GateCommonReconciler) FindAction(trigger gateshv1alpha1.GateActionTrigger, name string) *gateshv1alpha1.GateAction {
+	actions := g.Gate.Spec.Actions.OnOpen
+	if trigger == gateshv1alpha1.GateActionTriggerOnClose {
+		actions = g.Gate.Spec.Actions.OnClose
+	}
+	for idx := range actions {
+		if actions[idx].Name == name {
+			return &actions[idx]
+		}
+	}
+	return nil
+}
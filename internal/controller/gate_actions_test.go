@@ -0,0 +1,419 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateActions", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	getDeployment := func(cl client.Client) *appsv1.Deployment {
+		result := &appsv1.Deployment{}
+		Expect(cl.Get(ctx, types.NamespacedName{Name: "app", Namespace: "default"}, result)).To(Succeed())
+		return result
+	}
+
+	It("should execute the onOpen actions when the gate opens", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Actions: gateshv1alpha1.GateActions{
+					OnOpen: []gateshv1alpha1.GateAction{
+						{
+							Name: "Unpause",
+							Target: gateshv1alpha1.GateActionTarget{
+								ApiVersion: "apps/v1",
+								Kind:       "Deployment",
+								Name:       "app",
+							},
+							Patch: apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":false}}`)},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Paused:   true,
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap, deployment).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.Actions[0].Result).To(Equal(gateshv1alpha1.GateActionResultPending))
+		Expect(reconciler.RequeueAfter).To(Equal(ActionsStartDelay))
+		Expect(getDeployment(cl).Spec.Paused).To(BeTrue())
+
+		By("executing the actions once the transition is persisted")
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Actions).To(HaveLen(1))
+		Expect(gate.Status.Actions[0].Name).To(Equal("Unpause"))
+		Expect(gate.Status.Actions[0].Trigger).To(Equal(gateshv1alpha1.GateActionTriggerOnOpen))
+		Expect(gate.Status.Actions[0].Result).To(Equal(gateshv1alpha1.GateActionResultSucceeded))
+		Expect(gate.Status.Actions[0].Attempts).To(Equal(1))
+		Expect(getDeployment(cl).Spec.Paused).To(BeFalse())
+	})
+
+	It("should execute the actions only once per transition", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Actions: gateshv1alpha1.GateActions{
+					OnOpen: []gateshv1alpha1.GateAction{
+						{
+							Name: "Unpause",
+							Target: gateshv1alpha1.GateActionTarget{
+								ApiVersion: "apps/v1",
+								Kind:       "Deployment",
+								Name:       "app",
+							},
+							Patch: apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":false}}`)},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Paused:   true,
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap, deployment).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		By("pausing the deployment out of band")
+		patched := getDeployment(cl)
+		patched.Spec.Paused = true
+		Expect(cl.Update(ctx, patched)).To(Succeed())
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Actions[0].Attempts).To(Equal(1))
+		Expect(getDeployment(cl).Spec.Paused).To(BeTrue())
+	})
+
+	It("should not execute the onClose actions when the gate is created closed", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Actions: gateshv1alpha1.GateActions{
+					OnClose: []gateshv1alpha1.GateAction{
+						{
+							Name: "Pause",
+							Target: gateshv1alpha1.GateActionTarget{
+								ApiVersion: "apps/v1",
+								Kind:       "Deployment",
+								Name:       "app",
+							},
+							Patch: apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":true}}`)},
+						},
+					},
+				},
+			},
+		}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Paused:   true,
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, deployment).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.Actions).To(BeEmpty())
+	})
+
+	It("should execute the onClose actions when the gate closes", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Actions: gateshv1alpha1.GateActions{
+					OnClose: []gateshv1alpha1.GateAction{
+						{
+							Name: "Pause",
+							Target: gateshv1alpha1.GateActionTarget{
+								ApiVersion: "apps/v1",
+								Kind:       "Deployment",
+								Name:       "app",
+							},
+							Patch: apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":true}}`)},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Paused:   true,
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap, deployment).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(cl.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.Actions).To(HaveLen(1))
+		Expect(gate.Status.Actions[0].Name).To(Equal("Pause"))
+		Expect(gate.Status.Actions[0].Result).To(Equal(gateshv1alpha1.GateActionResultSucceeded))
+		Expect(getDeployment(cl).Spec.Paused).To(BeTrue())
+	})
+
+	It("should record the failure and retry with backoff", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Actions: gateshv1alpha1.GateActions{
+					OnOpen: []gateshv1alpha1.GateAction{
+						{
+							Name: "Unpause",
+							Target: gateshv1alpha1.GateActionTarget{
+								ApiVersion: "apps/v1",
+								Kind:       "Deployment",
+								Name:       "app",
+							},
+							Patch: apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":false}}`)},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Paused:   true,
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Actions[0].Result).To(Equal(gateshv1alpha1.GateActionResultFailed))
+		Expect(gate.Status.Actions[0].Message).To(ContainSubstring("failed to patch object default/app"))
+		Expect(reconciler.RequeueAfter).To(Equal(RetryBaseDelay))
+
+		By("reconciling again before the backoff elapsed")
+		Expect(cl.Create(ctx, deployment)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Actions[0].Attempts).To(Equal(1))
+
+		By("reconciling again after the backoff elapsed")
+		gate.Status.Actions[0].LastAttemptTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Actions[0].Attempts).To(Equal(2))
+		Expect(gate.Status.Actions[0].Result).To(Equal(gateshv1alpha1.GateActionResultSucceeded))
+		Expect(getDeployment(cl).Spec.Paused).To(BeFalse())
+	})
+
+	It("should not patch objects in another namespace than the gate's one", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Actions: gateshv1alpha1.GateActions{
+					OnOpen: []gateshv1alpha1.GateAction{
+						{
+							Name: "Unpause",
+							Target: gateshv1alpha1.GateActionTarget{
+								ApiVersion: "apps/v1",
+								Kind:       "Deployment",
+								Namespace:  "other",
+								Name:       "app",
+							},
+							Patch: apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":false}}`)},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		other := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "other"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Paused:   true,
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap, other).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Actions[0].Result).To(Equal(gateshv1alpha1.GateActionResultFailed))
+		Expect(gate.Status.Actions[0].Message).To(ContainSubstring("own namespace default"))
+		result := &appsv1.Deployment{}
+		Expect(cl.Get(ctx, types.NamespacedName{Name: "app", Namespace: "other"}, result)).To(Succeed())
+		Expect(result.Spec.Paused).To(BeTrue())
+	})
+
+	It("should keep the pending actions when the gate fails", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Actions: gateshv1alpha1.GateActions{
+					OnClose: []gateshv1alpha1.GateAction{
+						{
+							Name: "Pause",
+							Target: gateshv1alpha1.GateActionTarget{
+								ApiVersion: "apps/v1",
+								Kind:       "Deployment",
+								Name:       "app",
+							},
+							Patch: apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":true}}`)},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Paused:   true,
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap, deployment).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(cl.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Actions[0].Name).To(Equal("Pause"))
+		Expect(gate.Status.Actions[0].Result).To(Equal(gateshv1alpha1.GateActionResultPending))
+
+		reconciler.StartActions(gateshv1alpha1.GateStateFailed)
+		Expect(gate.Status.Actions).To(HaveLen(1))
+		Expect(gate.Status.Actions[0].Name).To(Equal("Pause"))
+		Expect(gate.Status.Actions[0].Result).To(Equal(gateshv1alpha1.GateActionResultPending))
+	})
+
+	It("should compute an exponential and capped retry delay", func() {
+		reconciler := GateCommonReconciler{}
+		Expect(reconciler.GetRetryDelay(1)).To(Equal(RetryBaseDelay))
+		Expect(reconciler.GetRetryDelay(2)).To(Equal(2 * RetryBaseDelay))
+		Expect(reconciler.GetRetryDelay(3)).To(Equal(4 * RetryBaseDelay))
+		Expect(reconciler.GetRetryDelay(100)).To(Equal(RetryMaxDelay))
+	})
+})
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/audit"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// WriteAuditRecord writes the audit record of the evaluation, if the auditor accepts it. Failures are only logged.
+func (g *GateCommonReconciler) WriteAuditRecord(previousState gateshv1alpha1.GateState) {
+	if g.Auditor == nil {
+		return
+	}
+	gate := audit.GateReference{
+		Kind:      g.GetGateKind(),
+		Namespace: g.Gate.Namespace,
+		Name:      g.Gate.Name,
+		UID:       string(g.Gate.UID),
+	}
+	transition := previousState != g.Gate.Status.State
+	if !g.Auditor.Accepts(gate, transition) {
+		return
+	}
+	record := g.BuildAuditRecord(gate, previousState)
+	if err := g.Auditor.Write(record); err != nil {
+		logf.FromContext(g.Context).Error(err, "unable to write audit record")
+	}
+}
+
+func (g *GateCommonReconciler) BuildAuditRecord(gate audit.GateReference, previousState gateshv1alpha1.GateState) audit.Record {
+	record := audit.Record{
+		Time:          time.Now().UTC(),
+		Gate:          gate,
+		Generation:    g.Gate.Generation,
+		Transition:    previousState != g.Gate.Status.State,
+		PreviousState: previousState,
+		State:         g.Gate.Status.State,
+		Targets:       make([]audit.TargetRecord, 0, len(g.Gate.Status.TargetConditions)),
+	}
+	// A forced state isn't evaluated: the record has no targets.
+	if forced := g.Gate.Status.Forced; forced != nil {
+		record.ForcedBy = forced.By
+		return record
+	}
+	for _, condition := range g.Gate.Status.TargetConditions {
+		target := audit.TargetRecord{
+			Name:    condition.Type,
+			Status:  condition.Status,
+			Reason:  condition.Reason,
+			Message: condition.Message,
+			Objects: make([]audit.ObjectRecord, 0),
+		}
+		for _, evaluation := range g.TargetEvaluations {
+			if evaluation.Target != condition.Type {
+				continue
+			}
+			for idx, object := range evaluation.Objects {
+				target.Objects = append(target.Objects, audit.ObjectRecord{
+					ApiVersion:      object.GetAPIVersion(),
+					Kind:            object.GetKind(),
+					Namespace:       object.GetNamespace(),
+					Name:            object.GetName(),
+					UID:             string(object.GetUID()),
+					ResourceVersion: object.GetResourceVersion(),
+					Valid:           evaluation.Results[idx],
+				})
+			}
+		}
+		record.Targets = append(record.Targets, target)
+	}
+	return record
+}
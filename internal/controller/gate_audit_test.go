@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/audit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateAudit", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var buffer *bytes.Buffer
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+		buffer = &bytes.Buffer{}
+	})
+
+	getRecords := func() []audit.Record {
+		records := make([]audit.Record, 0)
+		for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var record audit.Record
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			records = append(records, record)
+		}
+		return records
+	}
+
+	It("should audit the transitions with the evaluated objects", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", Generation: 3},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		auditor, err := audit.NewAuditor(buffer, audit.Options{Mode: audit.ModeTransition})
+		Expect(err).NotTo(HaveOccurred())
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Auditor: auditor}}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		records := getRecords()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Gate).To(Equal(audit.GateReference{Kind: "Gate", Namespace: "default", Name: "test-gate"}))
+		Expect(records[0].Generation).To(Equal(int64(3)))
+		Expect(records[0].Transition).To(BeTrue())
+		Expect(records[0].State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(records[0].Targets).To(HaveLen(1))
+		Expect(records[0].Targets[0].Status).To(Equal(metav1.ConditionTrue))
+		Expect(records[0].Targets[0].Objects).To(Equal([]audit.ObjectRecord{
+			{
+				ApiVersion:      "v1",
+				Kind:            "ConfigMap",
+				Namespace:       "default",
+				Name:            "config",
+				ResourceVersion: "999",
+				Valid:           true,
+			},
+		}))
+	})
+
+	It("should audit every evaluation of the selected gates", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		auditor, err := audit.NewAuditor(buffer, audit.Options{Mode: audit.ModeEvaluation, Gates: []string{"default/test-*"}})
+		Expect(err).NotTo(HaveOccurred())
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Auditor: auditor}}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		records := getRecords()
+		Expect(records).To(HaveLen(2))
+		Expect(records[1].Transition).To(BeFalse())
+		Expect(records[1].PreviousState).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(records[1].Targets[0].Objects).To(BeEmpty())
+
+		By("ignoring the other gates")
+		gate.Name = "other-gate"
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(getRecords()).To(HaveLen(2))
+	})
+
+	It("should audit the forced transitions with the user who forced them", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-gate",
+				Namespace: "default",
+				Annotations: map[string]string{
+					gateshv1alpha1.GateForceStateAnnotation: gateshv1alpha1.GateStateOpened,
+					gateshv1alpha1.GateForcedByAnnotation:   "alice",
+				},
+			},
+			Status: gateshv1alpha1.GateStatus{State: gateshv1alpha1.GateStateClosed},
+		}
+
+		auditor, err := audit.NewAuditor(buffer, audit.Options{Mode: audit.ModeTransition})
+		Expect(err).NotTo(HaveOccurred())
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Auditor: auditor}}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		records := getRecords()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Transition).To(BeTrue())
+		Expect(records[0].PreviousState).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(records[0].State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(records[0].ForcedBy).To(Equal("alice"))
+		Expect(records[0].Targets).To(BeEmpty())
+	})
+})
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/notifier"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func (g *GateCommonReconciler) EmitTransitionCloudEvent(previousState gateshv1alpha1.GateState) {
+	eventType := notifier.CloudEventTypeClosed
+	switch g.Gate.Status.State {
+	case gateshv1alpha1.GateStateOpened:
+		eventType = notifier.CloudEventTypeOpened
+	case gateshv1alpha1.GateStateFailed:
+		eventType = notifier.CloudEventTypeFailed
+	}
+	g.EmitCloudEvent(eventType, g.BuildGateEventData(previousState))
+}
+
+// EmitEvaluationCloudEvents emits the sh.gate.evaluated event of the evaluation, and a sh.gate.targetChanged event
+// per target whose condition status changed since the previous evaluation.
+func (g *GateCommonReconciler) EmitEvaluationCloudEvents(previousState gateshv1alpha1.GateState, previousTargetConditions []metav1.Condition) {
+	if g.CloudEvents == nil {
+		return
+	}
+
+	g.EmitCloudEvent(notifier.CloudEventTypeEvaluated, g.BuildGateEventData(previousState))
+	for _, condition := range g.GetChangedTargetConditions(previousTargetConditions) {
+		data := g.BuildGateEventData(previousState)
+		data.Target = condition
+		g.EmitCloudEvent(notifier.CloudEventTypeTargetChanged, data)
+	}
+}
+
+func (g *GateCommonReconciler) EmitCloudEvent(eventType string, data notifier.GateEventData) {
+	if g.CloudEvents == nil {
+		return
+	}
+	if err := g.CloudEvents.Emit(eventType, data); err != nil {
+		logf.FromContext(g.Context).Error(err, "unable to emit CloudEvent", "type", eventType)
+	}
+}
+
+func (g *GateCommonReconciler) BuildGateEventData(previousState gateshv1alpha1.GateState) notifier.GateEventData {
+	return notifier.GateEventData{
+		Gate: notifier.GateReference{
+			Kind:      g.GetGateKind(),
+			Namespace: g.Gate.Namespace,
+			Name:      g.Gate.Name,
+			UID:       string(g.Gate.UID),
+		},
+		PreviousState:               previousState,
+		State:                       g.Gate.Status.State,
+		TargetConditions:            g.Gate.Status.TargetConditions,
+		ConsecutiveValidEvaluations: g.Gate.Status.ConsecutiveValidEvaluations,
+	}
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/notifier"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateCloudEvents", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var server *httptest.Server
+	var lock sync.Mutex
+	var eventTypes []string
+
+	getEventTypes := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return eventTypes
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		eventTypes = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			eventTypes = append(eventTypes, r.Header.Get("ce-type"))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	newReconciler := func(gate *gateshv1alpha1.Gate, objects ...runtime.Object) *GateCommonReconciler {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).WithRuntimeObjects(objects...).Build()
+		emitter, err := notifier.NewCloudEventEmitter(server.URL, notifier.CloudEventModeBinary, "gate-operator")
+		Expect(err).NotTo(HaveOccurred())
+		emitterCtx, cancel := context.WithCancel(ctx)
+		DeferCleanup(cancel)
+		go func() { _ = emitter.Start(emitterCtx) }()
+		return &GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{CloudEvents: emitter}}
+	}
+
+	It("should emit an evaluated event on every evaluation", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		reconciler := newReconciler(gate)
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Eventually(getEventTypes).Should(Equal([]string{notifier.CloudEventTypeEvaluated, notifier.CloudEventTypeEvaluated}))
+	})
+
+	It("should emit the transition and the target change events", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := newReconciler(gate)
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(reconciler.Client.Create(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Eventually(getEventTypes).Should(Equal([]string{
+			notifier.CloudEventTypeEvaluated,
+			notifier.CloudEventTypeOpened,
+			notifier.CloudEventTypeEvaluated,
+			notifier.CloudEventTypeTargetChanged,
+		}))
+	})
+})
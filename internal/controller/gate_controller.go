@@ -22,21 +22,27 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/metrics"
 )
 
 // GateReconciler reconciles a Gate object
 type GateReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	GateOutputs
 }
 
 // +kubebuilder:rbac:groups=gate.sh,resources=gates,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gate.sh,resources=gates/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=gate.sh,resources=gates/finalizers,verbs=update
+// +kubebuilder:rbac:groups=gate.sh,resources=notificationchannels,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="*",resources="*",verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -56,16 +62,28 @@ func (r *GateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	err := r.Get(ctx, req.NamespacedName, &gate)
 	if errors.IsNotFound(err) {
 		log.Info("Gate not found")
+		metrics.DeleteGate(metrics.GateLabels{Kind: "Gate", Namespace: req.Namespace, Name: req.Name})
+		if r.Debug != nil {
+			r.Debug.Delete("Gate", req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to fetch Gate")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if AddGateFinalizers(&gate, &gate.Spec) {
+		if err := r.Update(ctx, &gate); err != nil {
+			log.Error(err, "unable to add finalizers to Gate")
+			return ctrl.Result{}, err
+		}
+	}
+
 	gcr := GateCommonReconciler{
-		Context: ctx,
-		Client:  r.Client,
-		Gate:    &gate,
+		Context:     ctx,
+		Client:      r.Client,
+		GateOutputs: r.GateOutputs,
+		Gate:        &gate,
 	}
 	err = gcr.Reconcile()
 	if err != nil {
@@ -74,6 +92,14 @@ func (r *GateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	}
 	if err := r.Status().Update(ctx, &gate); err != nil {
 		log.Error(err, "unable to update Gate")
+		return ctrl.Result{}, err
+	}
+	// Once the protected objects are released and the exported state removed, the gate can be deleted.
+	if gcr.RemoveGateFinalizers(&gate) {
+		if err := r.Update(ctx, &gate); err != nil {
+			log.Error(err, "unable to remove finalizers from Gate")
+			return ctrl.Result{RequeueAfter: gcr.RequeueAfter}, err
+		}
 	}
 	return ctrl.Result{RequeueAfter: gcr.RequeueAfter}, err
 }
@@ -81,7 +107,7 @@ func (r *GateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 // SetupWithManager sets up the controller with the Manager.
 func (r *GateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&gateshv1alpha1.Gate{}).
+		For(&gateshv1alpha1.Gate{}, builder.WithPredicates(StatusUpdateIgnoredPredicate())).
 		Named("gate").
 		// WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
 		Complete(r)
@@ -105,6 +105,7 @@ var _ = Describe("Gate Controller", func() {
 	client := fake.NewClientBuilder().
 		WithScheme(scheme).
 		WithObjects(gate1, cm1, deploy1).
+		WithStatusSubresource(gate1).
 		Build()
 
 	Context("When reconciling a non-existing resource", func() {
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"fmt"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	GateForcedReason    = "Forced"
+	GateSuspendedReason = "Suspended"
+)
+
+// HandleReevaluate returns true when the gate.sh/reevaluate annotation changed since the last evaluation. The
+// annotation changes trigger a reconciliation right away, and the gate is evaluated even if its timeout stopped it.
+// The request is only recorded as handled by an evaluation, see RecordReevaluate.
+func (g *GateCommonReconciler) HandleReevaluate() bool {
+	value := g.Gate.GetAnnotations()[gateshv1alpha1.GateReevaluateAnnotation]
+	if value == "" || value == g.Gate.Status.LastHandledReevaluate {
+		return false
+	}
+	logf.FromContext(g.Context).Info(fmt.Sprintf("%s %s reevaluation requested", g.Gate.Kind, g.Gate.Name), "value", value)
+	return true
+}
+
+// RecordReevaluate records the gate.sh/reevaluate annotation as handled once the gate was evaluated. A request made
+// while the gate is suspended, forced or latched is handled by its next evaluation.
+func (g *GateCommonReconciler) RecordReevaluate() {
+	if value := g.Gate.GetAnnotations()[gateshv1alpha1.GateReevaluateAnnotation]; value != "" {
+		g.Gate.Status.LastHandledReevaluate = value
+	}
+}
+
+// ApplySuspend maintains the Suspended condition. Returns true if the gate is suspended: its state is frozen and it
+// isn't evaluated.
+func (g *GateCommonReconciler) ApplySuspend() bool {
+	if !g.Gate.Spec.Suspend {
+		meta.RemoveStatusCondition(&g.Gate.Status.Conditions, gateshv1alpha1.GateConditionSuspended)
+		return false
+	}
+	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{
+		Type:               gateshv1alpha1.GateConditionSuspended,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: g.Gate.Generation,
+		Reason:             GateSuspendedReason,
+		Message:            fmt.Sprintf("%s evaluation is suspended by its spec", g.GetGateKind()),
+	})
+	return true
+}
+
+// ApplyForcedState applies the state forced by the gate.sh/force-state annotation, recording who forced it. A
+// forced transition is handled as any other transition, and unlatches the gate. Returns true if the state is forced:
+// the gate isn't evaluated until the annotation is removed.
+func (g *GateCommonReconciler) ApplyForcedState() bool {
+	annotations := g.Gate.GetAnnotations()
+	state := annotations[gateshv1alpha1.GateForceStateAnnotation]
+	if state != gateshv1alpha1.GateStateOpened && state != gateshv1alpha1.GateStateClosed {
+		g.Gate.Status.Forced = nil
+		return false
+	}
+
+	forcedBy := annotations[gateshv1alpha1.GateForcedByAnnotation]
+	if forcedBy == "" {
+		forcedBy = "unknown"
+	}
+	message := fmt.Sprintf("State forced to %s by %s", state, forcedBy)
+	forced := g.Gate.Status.Forced
+	if forced == nil || forced.State != state || forced.By != forcedBy {
+		g.Gate.Status.Forced = &gateshv1alpha1.GateForcedState{State: state, By: forcedBy, Time: metav1.Now()}
+		logf.FromContext(g.Context).Info(fmt.Sprintf("%s %s state forced", g.Gate.Kind, g.Gate.Name), "state", state, "by", forcedBy)
+		if g.Recorder != nil {
+			g.Recorder.Event(g.Gate, corev1.EventTypeWarning, EventReasonForced, message)
+		}
+	}
+	g.Gate.Status.LatchedAt = nil
+	meta.RemoveStatusCondition(&g.Gate.Status.Conditions, gateshv1alpha1.GateConditionTimedOut)
+
+	opened, closed := metav1.ConditionTrue, metav1.ConditionFalse
+	if state == gateshv1alpha1.GateStateClosed {
+		opened, closed = closed, opened
+	}
+	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateStateOpened, Status: opened, Reason: GateForcedReason, Message: message})
+	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateStateClosed, Status: closed, Reason: GateForcedReason, Message: message})
+
+	previousState := g.Gate.Status.State
+	g.Gate.Status.State = state
+	if previousState != state {
+		g.HandleStateTransition(previousState)
+		g.WriteAuditRecord(previousState)
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateControls", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should freeze the state of a suspended gate", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+
+		By("not evaluating the suspended gate")
+		gate.Spec.Suspend = true
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		lastEvaluationTime := gate.Status.LastEvaluationTime
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.LastEvaluationTime).To(Equal(lastEvaluationTime))
+		Expect(meta.IsStatusConditionTrue(gate.Status.Conditions, gateshv1alpha1.GateConditionSuspended)).To(BeTrue())
+		Expect(reconciler.RequeueAfter).To(BeZero())
+
+		By("evaluating the gate again once resumed")
+		gate.Spec.Suspend = false
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionSuspended)).To(BeNil())
+	})
+
+	It("should evaluate a failed gate when the reevaluate annotation changes", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-gate",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			},
+			Spec: gateshv1alpha1.GateSpec{
+				Timeout: &metav1.Duration{Duration: time.Hour},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+
+		By("evaluating the gate once per annotation value")
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		gate.Annotations = map[string]string{gateshv1alpha1.GateReevaluateAnnotation: "1"}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.LastHandledReevaluate).To(Equal("1"))
+		Expect(reconciler.HandleReevaluate()).To(BeFalse())
+	})
+
+	It("should keep a reevaluation requested while the gate is suspended", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-gate",
+				Namespace:   "default",
+				Annotations: map[string]string{gateshv1alpha1.GateReevaluateAnnotation: "1"},
+			},
+			Spec: gateshv1alpha1.GateSpec{
+				Suspend: true,
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.LastEvaluationTime).To(BeNil())
+		Expect(gate.Status.LastHandledReevaluate).To(BeEmpty())
+
+		By("handling the request once resumed")
+		gate.Spec.Suspend = false
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.LastEvaluationTime).NotTo(BeNil())
+		Expect(gate.Status.LastHandledReevaluate).To(Equal("1"))
+	})
+
+	It("should force the state of the gate and record who forced it", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		recorder := record.NewFakeRecorder(20)
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Recorder: NewGateEventRecorder(recorder)}}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventReasonClosed)))
+
+		By("opening the gate by force")
+		gate.Annotations = map[string]string{
+			gateshv1alpha1.GateForceStateAnnotation: gateshv1alpha1.GateStateOpened,
+			gateshv1alpha1.GateForcedByAnnotation:   "alice",
+		}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.Forced).NotTo(BeNil())
+		Expect(gate.Status.Forced.By).To(Equal("alice"))
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened).Reason).To(Equal(GateForcedReason))
+		Expect(gate.Status.History).To(HaveLen(2))
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventReasonForced)))
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventReasonOpened)))
+
+		By("keeping the forced state without evaluating the targets")
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.History).To(HaveLen(2))
+
+		By("evaluating the gate again once the annotation is removed")
+		gate.Annotations = nil
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.Forced).To(BeNil())
+		Expect(gate.Status.History).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,329 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/webhook/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DebugGatesPath lists the last evaluation of all the gates, and serves the gates under {namespace}/{name}.
+	DebugGatesPath = "/debug/gates"
+	// DebugClusterGatesPath serves the cluster gates under {name}.
+	DebugClusterGatesPath = "/debug/clustergates"
+)
+
+type GateDebugObject struct {
+	ApiVersion      string                                      `json:"apiVersion"`
+	Kind            string                                      `json:"kind"`
+	Namespace       string                                      `json:"namespace,omitempty"`
+	Name            string                                      `json:"name"`
+	ResourceVersion string                                      `json:"resourceVersion"`
+	Valid           bool                                        `json:"valid"`
+	Failures        []gateshv1alpha1.GateTargetValidatorFailure `json:"failures,omitempty"`
+}
+
+type GateDebugTarget struct {
+	Name      string            `json:"name"`
+	Condition metav1.Condition  `json:"condition"`
+	Duration  string            `json:"duration"`
+	Objects   []GateDebugObject `json:"objects"`
+}
+
+type GateDebugEvaluation struct {
+	Kind       string                  `json:"kind"`
+	Namespace  string                  `json:"namespace,omitempty"`
+	Name       string                  `json:"name"`
+	Generation int64                   `json:"generation"`
+	Time       metav1.Time             `json:"time"`
+	Duration   string                  `json:"duration"`
+	DryRun     bool                    `json:"dryRun"`
+	Spec       gateshv1alpha1.GateSpec `json:"spec"`
+	Result     bool                    `json:"result"`
+	State      string                  `json:"state"`
+	// Control skipping the evaluation of the gate by the controller, for the dry runs: Forced, Suspended, Latched or
+	// TimedOut. The state is then the one the controller keeps, whatever the result.
+	Control string            `json:"control,omitempty"`
+	Targets []GateDebugTarget `json:"targets"`
+}
+
+func (e *GateDebugEvaluation) key() string {
+	return GetGateDebugKey(e.Kind, e.Namespace, e.Name)
+}
+
+func GetGateDebugKey(kind string, namespace string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+type GateDebugStore struct {
+	mutex       sync.RWMutex
+	evaluations map[string]GateDebugEvaluation
+}
+
+func NewGateDebugStore() *GateDebugStore {
+	return &GateDebugStore{evaluations: make(map[string]GateDebugEvaluation)}
+}
+
+func (s *GateDebugStore) Set(evaluation GateDebugEvaluation) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.evaluations[evaluation.key()] = evaluation
+}
+
+func (s *GateDebugStore) Delete(kind string, namespace string, name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.evaluations, GetGateDebugKey(kind, namespace, name))
+}
+
+func (s *GateDebugStore) Get(kind string, namespace string, name string) (GateDebugEvaluation, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	evaluation, ok := s.evaluations[GetGateDebugKey(kind, namespace, name)]
+	return evaluation, ok
+}
+
+func (s *GateDebugStore) List() []GateDebugEvaluation {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	evaluations := make([]GateDebugEvaluation, 0, len(s.evaluations))
+	for _, evaluation := range s.evaluations {
+		evaluations = append(evaluations, evaluation)
+	}
+	sort.Slice(evaluations, func(i, j int) bool { return evaluations[i].key() < evaluations[j].key() })
+	return evaluations
+}
+
+func (g *GateCommonReconciler) RecordDebugEvaluation(result bool, targetConditions []metav1.Condition, duration time.Duration) {
+	if g.Debug == nil {
+		return
+	}
+	g.Debug.Set(g.BuildDebugEvaluation(result, targetConditions, duration))
+}
+
+func (g *GateCommonReconciler) BuildDebugEvaluation(result bool, targetConditions []metav1.Condition, duration time.Duration) GateDebugEvaluation {
+	evaluation := GateDebugEvaluation{
+		Kind:       g.GetGateKind(),
+		Namespace:  g.Gate.Namespace,
+		Name:       g.Gate.Name,
+		Generation: g.Gate.Generation,
+		Time:       metav1.Now(),
+		Duration:   duration.String(),
+		DryRun:     g.DryRun,
+		Spec:       *g.Gate.Spec.DeepCopy(),
+		Result:     result,
+		State:      g.Gate.Status.State,
+		Targets:    make([]GateDebugTarget, 0, len(targetConditions)),
+	}
+	for _, condition := range targetConditions {
+		target := GateDebugTarget{Name: condition.Type, Condition: condition, Objects: make([]GateDebugObject, 0)}
+		for _, targetEvaluation := range g.TargetEvaluations {
+			if targetEvaluation.Target != condition.Type {
+				continue
+			}
+			target.Duration = targetEvaluation.Duration.String()
+			for idx, object := range targetEvaluation.Objects {
+				target.Objects = append(target.Objects, GateDebugObject{
+					ApiVersion:      object.GetAPIVersion(),
+					Kind:            object.GetKind(),
+					Namespace:       object.GetNamespace(),
+					Name:            object.GetName(),
+					ResourceVersion: object.GetResourceVersion(),
+					Valid:           targetEvaluation.Results[idx],
+					Failures:        targetEvaluation.Failures[idx],
+				})
+			}
+		}
+		evaluation.Targets = append(evaluation.Targets, target)
+	}
+	return evaluation
+}
+
+// DryRunEvaluation evaluates a copy of the gate without persisting its status nor triggering its side effects. The
+// targets of a forced, suspended, latched or stopped gate are evaluated, but its state is the one the controller
+// keeps.
+func DryRunEvaluation(ctx context.Context, cl client.Client, gate *gateshv1alpha1.Gate) GateDebugEvaluation {
+	g := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate.DeepCopy(), DryRun: true}
+	v1alpha1.ApplyDefaultSpec(&g.Gate.Spec)
+	control := g.GetEvaluationControl()
+	start := time.Now()
+	result, targetConditions, targetStatuses := g.EvaluateSpec()
+	duration := time.Since(start)
+	switch control {
+	case "":
+		g.UpdateGateStatusFromResult(result, targetConditions, targetStatuses)
+	case GateForcedReason:
+		g.Gate.Status.State = g.Gate.GetAnnotations()[gateshv1alpha1.GateForceStateAnnotation]
+	}
+	evaluation := g.BuildDebugEvaluation(result == metav1.ConditionTrue, targetConditions, duration)
+	evaluation.Control = control
+	return evaluation
+}
+
+// GetEvaluationControl returns the control skipping the evaluation of the gate, following the checks of Reconcile:
+// Forced, Suspended, Latched or TimedOut. Returns an empty string if the gate is evaluated.
+func (g *GateCommonReconciler) GetEvaluationControl() string {
+	annotations := g.Gate.GetAnnotations()
+	status := &g.Gate.Status
+	forcedState := annotations[gateshv1alpha1.GateForceStateAnnotation]
+	latchReset := annotations[gateshv1alpha1.GateResetLatchAnnotation]
+	reevaluate := annotations[gateshv1alpha1.GateReevaluateAnnotation]
+	switch {
+	case forcedState == gateshv1alpha1.GateStateOpened || forcedState == gateshv1alpha1.GateStateClosed:
+		return GateForcedReason
+	case g.Gate.Spec.Suspend:
+		return GateSuspendedReason
+	case g.Gate.Spec.Latch && status.LatchedAt != nil && (latchReset == "" || latchReset == status.LastHandledLatchReset):
+		return GateLatchedReason
+	case (reevaluate == "" || reevaluate == status.LastHandledReevaluate) && status.LastEvaluatedGeneration == g.Gate.Generation &&
+		g.IsEvaluationStopped():
+		return GateTimedOutReason
+	}
+	return ""
+}
+
+// GateDebugHandler serves the debug endpoint. It must be protected by authentication, as it exposes the evaluated
+// objects.
+type GateDebugHandler struct {
+	Client client.Client
+	Store  *GateDebugStore
+	// Elected is closed once the replica is elected leader, the replica being the leader if nil. Only the leader
+	// evaluates the gates and fills the store: the other replicas serve dry runs computed from their cache.
+	Elected <-chan struct{}
+}
+
+func (h *GateDebugHandler) IsLeader() bool {
+	if h.Elected == nil {
+		return true
+	}
+	select {
+	case <-h.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+func (h *GateDebugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	kind, namespace, name, ok := ParseDebugPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	leader := h.IsLeader()
+	if kind == "" && leader {
+		writeDebugJSON(w, http.StatusOK, h.Store.List())
+		return
+	}
+	if kind == "" {
+		evaluations, err := h.DryRunAll(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeDebugJSON(w, http.StatusOK, evaluations)
+		return
+	}
+
+	if r.URL.Query().Get("evaluate") == "now" || !leader {
+		gate, err := h.GetGate(r.Context(), kind, namespace, name)
+		if errors.IsNotFound(err) {
+			http.NotFound(w, r)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeDebugJSON(w, http.StatusOK, DryRunEvaluation(r.Context(), h.Client, gate))
+		return
+	}
+
+	evaluation, found := h.Store.Get(kind, namespace, name)
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	writeDebugJSON(w, http.StatusOK, evaluation)
+}
+
+// ParseDebugPath returns the gate designated by the path, an empty kind designating the list of the gates.
+func ParseDebugPath(path string) (kind string, namespace string, name string, ok bool) {
+	path = strings.TrimSuffix(path, "/")
+	if path == DebugGatesPath {
+		return "", "", "", true
+	}
+	if rest, found := strings.CutPrefix(path, DebugGatesPath+"/"); found {
+		parts := strings.Split(rest, "/")
+		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			return "Gate", parts[0], parts[1], true
+		}
+	}
+	if rest, found := strings.CutPrefix(path, DebugClusterGatesPath+"/"); found {
+		if rest != "" && !strings.Contains(rest, "/") {
+			return "ClusterGate", "", rest, true
+		}
+	}
+	return "", "", "", false
+}
+
+func (h *GateDebugHandler) GetGate(ctx context.Context, kind string, namespace string, name string) (*gateshv1alpha1.Gate, error) {
+	if kind == "ClusterGate" {
+		var clusterGate gateshv1alpha1.ClusterGate
+		if err := h.Client.Get(ctx, client.ObjectKey{Name: name}, &clusterGate); err != nil {
+			return nil, err
+		}
+		gate := ClusterGateAsGate(&clusterGate)
+		return &gate, nil
+	}
+	var gate gateshv1alpha1.Gate
+	if err := h.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &gate); err != nil {
+		return nil, err
+	}
+	return &gate, nil
+}
+
+// DryRunAll evaluates all the gates and cluster gates without persisting them, sorted as the store lists them.
+func (h *GateDebugHandler) DryRunAll(ctx context.Context) ([]GateDebugEvaluation, error) {
+	var gates gateshv1alpha1.GateList
+	if err := h.Client.List(ctx, &gates); err != nil {
+		return nil, err
+	}
+	var clusterGates gateshv1alpha1.ClusterGateList
+	if err := h.Client.List(ctx, &clusterGates); err != nil {
+		return nil, err
+	}
+	evaluations := make([]GateDebugEvaluation, 0, len(gates.Items)+len(clusterGates.Items))
+	for idx := range gates.Items {
+		evaluations = append(evaluations, DryRunEvaluation(ctx, h.Client, &gates.Items[idx]))
+	}
+	for idx := range clusterGates.Items {
+		gate := ClusterGateAsGate(&clusterGates.Items[idx])
+		evaluations = append(evaluations, DryRunEvaluation(ctx, h.Client, &gate))
+	}
+	sort.Slice(evaluations, func(i, j int) bool { return evaluations[i].key() < evaluations[j].key() })
+	return evaluations, nil
+}
+
+func writeDebugJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(body)
+}
@@ -0,0 +1,264 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateDebug", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var store *GateDebugStore
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+		store = NewGateDebugStore()
+	})
+
+	get := func(handler http.Handler, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	It("should keep the last evaluation of the gate", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						Validators: []gateshv1alpha1.GateTargetValidator{
+							{JsonPointer: gateshv1alpha1.GateTargetValidatorJsonPointer{Pointer: "/data/ready", Value: "true"}},
+						},
+					},
+				},
+			},
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"ready": "false"},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Debug: store}}
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		evaluation, found := store.Get("Gate", "default", "test-gate")
+		Expect(found).To(BeTrue())
+		Expect(evaluation.DryRun).To(BeFalse())
+		Expect(evaluation.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(evaluation.Spec.Consolidation.Count).To(Equal(1)) // Resolved by the defaults
+		Expect(evaluation.Targets).To(HaveLen(1))
+		Expect(evaluation.Targets[0].Duration).NotTo(BeEmpty())
+		Expect(evaluation.Targets[0].Objects).To(HaveLen(1))
+		Expect(evaluation.Targets[0].Objects[0].Valid).To(BeFalse())
+		Expect(evaluation.Targets[0].Objects[0].Failures).To(HaveLen(1))
+		Expect(evaluation.Targets[0].Objects[0].Failures[0].Validator).To(Equal(gateshv1alpha1.GateValidatorJsonPointer))
+	})
+
+	It("should serve the evaluations", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Debug: store}}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		handler := &GateDebugHandler{Client: cl, Store: store}
+
+		response := get(handler, "/debug/gates")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		var evaluations []GateDebugEvaluation
+		Expect(json.Unmarshal(response.Body.Bytes(), &evaluations)).To(Succeed())
+		Expect(evaluations).To(HaveLen(1))
+
+		response = get(handler, "/debug/gates/default/test-gate")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		var evaluation GateDebugEvaluation
+		Expect(json.Unmarshal(response.Body.Bytes(), &evaluation)).To(Succeed())
+		Expect(evaluation.Name).To(Equal("test-gate"))
+
+		Expect(get(handler, "/debug/gates/default/other-gate").Code).To(Equal(http.StatusNotFound))
+		Expect(get(handler, "/debug/clustergates/test-gate").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should evaluate the gate without persisting it", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						Validators: []gateshv1alpha1.GateTargetValidator{
+							{JsonPointer: gateshv1alpha1.GateTargetValidatorJsonPointer{Pointer: "/data/ready", Value: "true"}},
+						},
+					},
+				},
+			},
+			Status: gateshv1alpha1.GateStatus{State: gateshv1alpha1.GateStateClosed},
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"ready": "true"},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).WithStatusSubresource(gate).Build()
+		handler := &GateDebugHandler{Client: cl, Store: store}
+
+		response := get(handler, "/debug/gates/default/test-gate?evaluate=now")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		var evaluation GateDebugEvaluation
+		Expect(json.Unmarshal(response.Body.Bytes(), &evaluation)).To(Succeed())
+		Expect(evaluation.DryRun).To(BeTrue())
+		Expect(evaluation.Result).To(BeTrue())
+		Expect(evaluation.State).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		var persisted gateshv1alpha1.Gate
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(gate), &persisted)).To(Succeed())
+		Expect(persisted.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(store.List()).To(BeEmpty())
+
+		Expect(get(handler, "/debug/gates/default/missing?evaluate=now").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should serve dry runs on the replicas which are not the leader", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		elected := make(chan struct{})
+		handler := &GateDebugHandler{Client: cl, Store: store, Elected: elected}
+
+		response := get(handler, "/debug/gates")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		var evaluations []GateDebugEvaluation
+		Expect(json.Unmarshal(response.Body.Bytes(), &evaluations)).To(Succeed())
+		Expect(evaluations).To(HaveLen(1))
+		Expect(evaluations[0].DryRun).To(BeTrue())
+		Expect(evaluations[0].State).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		response = get(handler, "/debug/gates/default/test-gate")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		var evaluation GateDebugEvaluation
+		Expect(json.Unmarshal(response.Body.Bytes(), &evaluation)).To(Succeed())
+		Expect(evaluation.DryRun).To(BeTrue())
+
+		By("serving the store once elected")
+		close(elected)
+		Expect(get(handler, "/debug/gates/default/test-gate").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should keep the state of the gates the controller doesn't evaluate", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						Validators: []gateshv1alpha1.GateTargetValidator{
+							{JsonPointer: gateshv1alpha1.GateTargetValidatorJsonPointer{Pointer: "/data/ready", Value: "true"}},
+						},
+					},
+				},
+			},
+			Status: gateshv1alpha1.GateStatus{State: gateshv1alpha1.GateStateClosed},
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"ready": "true"},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+		gate.Spec.Suspend = true
+		evaluation := DryRunEvaluation(ctx, cl, gate)
+		Expect(evaluation.Result).To(BeTrue())
+		Expect(evaluation.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(evaluation.Control).To(Equal(GateSuspendedReason))
+
+		gate.Annotations = map[string]string{gateshv1alpha1.GateForceStateAnnotation: gateshv1alpha1.GateStateClosed}
+		gate.Status.State = gateshv1alpha1.GateStateOpened
+		evaluation = DryRunEvaluation(ctx, cl, gate)
+		Expect(evaluation.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(evaluation.Control).To(Equal(GateForcedReason))
+
+		gate.Annotations = nil
+		gate.Spec.Suspend = false
+		gate.Spec.Latch = true
+		gate.Status.LatchedAt = &metav1.Time{}
+		evaluation = DryRunEvaluation(ctx, cl, gate)
+		Expect(evaluation.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(evaluation.Control).To(Equal(GateLatchedReason))
+
+		gate.Annotations = map[string]string{gateshv1alpha1.GateResetLatchAnnotation: "now"}
+		Expect(DryRunEvaluation(ctx, cl, gate).Control).To(BeEmpty())
+	})
+
+	It("should parse the paths", func() {
+		kind, namespace, name, ok := ParseDebugPath("/debug/gates/")
+		Expect([]any{kind, namespace, name, ok}).To(Equal([]any{"", "", "", true}))
+		kind, namespace, name, ok = ParseDebugPath("/debug/clustergates/platform")
+		Expect([]any{kind, namespace, name, ok}).To(Equal([]any{"ClusterGate", "", "platform", true}))
+		_, _, _, ok = ParseDebugPath("/debug/gates/default")
+		Expect(ok).To(BeFalse())
+		_, _, _, ok = ParseDebugPath("/debug/clustergates/")
+		Expect(ok).To(BeFalse())
+	})
+})
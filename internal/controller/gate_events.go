@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	EventReasonOpened                     = "Opened"
+	EventReasonClosed                     = "Closed"
+	EventReasonTargetValidated            = "TargetValidated"
+	EventReasonTargetInvalidated          = "TargetInvalidated"
+	EventReasonConsolidationProgress      = "ConsolidationProgress"
+	EventReasonCloseConsolidationProgress = "CloseConsolidationProgress"
+	EventReasonFetchError                 = "FetchError"
+	EventReasonTimedOut                   = "TimedOut"
+	EventReasonForced                     = "Forced"
+	EventReasonFlapping                   = "Flapping"
+	EventReasonFlappingCleared            = "FlappingCleared"
+)
+
+// EventThrottleInterval is the minimum delay between two identical throttled events of a gate.
+var EventThrottleInterval = 5 * time.Minute
+
+// GateEventRecorder records the Kubernetes events of the gates. Repeated identical throttled events are only recorded
+// once per EventThrottleInterval, so errors occurring at every evaluation don't flood the events API.
+type GateEventRecorder struct {
+	Recorder record.EventRecorder
+
+	mutex      sync.Mutex
+	lastEvents map[string]time.Time
+}
+
+func NewGateEventRecorder(recorder record.EventRecorder) *GateEventRecorder {
+	return &GateEventRecorder{Recorder: recorder, lastEvents: make(map[string]time.Time)}
+}
+
+func (r *GateEventRecorder) Event(object runtime.Object, eventType string, reason string, message string) {
+	r.Recorder.Event(object, eventType, reason, message)
+}
+
+func (r *GateEventRecorder) ThrottledEvent(gate *gateshv1alpha1.Gate, eventType string, reason string, message string) {
+	r.mutex.Lock()
+	now := time.Now()
+	for key, lastTime := range r.lastEvents {
+		if now.Sub(lastTime) >= EventThrottleInterval {
+			delete(r.lastEvents, key)
+		}
+	}
+	key := fmt.Sprintf("%s/%s/%s/%s/%s", gate.UID, gate.Namespace, gate.Name, reason, message)
+	_, throttled := r.lastEvents[key]
+	if !throttled {
+		r.lastEvents[key] = now
+	}
+	r.mutex.Unlock()
+
+	if !throttled {
+		r.Recorder.Event(gate, eventType, reason, message)
+	}
+}
+
+func (g *GateCommonReconciler) RecordTransitionEvent(previousState gateshv1alpha1.GateState) {
+	if g.Recorder == nil {
+		return
+	}
+	if g.Gate.Status.State == gateshv1alpha1.GateStateOpened {
+		g.Recorder.Event(g.Gate, corev1.EventTypeNormal, EventReasonOpened, fmt.Sprintf("%s opened", g.GetGateKind()))
+		return
+	}
+	if g.Gate.Status.State == gateshv1alpha1.GateStateFailed {
+		g.Recorder.Event(g.Gate, corev1.EventTypeWarning, EventReasonTimedOut,
+			fmt.Sprintf("%s did not open within %s", g.GetGateKind(), g.Gate.Spec.Timeout.Duration))
+		return
+	}
+
+	message := fmt.Sprintf("%s closed", g.GetGateKind())
+	if failing := g.GetFailingTargets(); len(failing) > 0 {
+		message = fmt.Sprintf("%s, failing targets: %v", message, failing)
+	}
+	eventType := corev1.EventTypeWarning
+	if previousState == "" {
+		eventType = corev1.EventTypeNormal
+	}
+	g.Recorder.Event(g.Gate, eventType, EventReasonClosed, message)
+}
+
+// RecordEvaluationEvents records the events of the target condition transitions and of the consolidation progress.
+// The progress is only recorded when the consolidation counters changed, not while waiting for a soak period.
+func (g *GateCommonReconciler) RecordEvaluationEvents(previousTargetConditions []metav1.Condition, previousConsecutiveValid int, previousConsecutiveInvalid int) {
+	if g.Recorder == nil {
+		return
+	}
+
+	for _, condition := range g.GetChangedTargetConditions(previousTargetConditions) {
+		if condition.Status == metav1.ConditionTrue {
+			g.Recorder.Event(g.Gate, corev1.EventTypeNormal, EventReasonTargetValidated,
+				fmt.Sprintf("Target %s is validated", condition.Type))
+		} else {
+			g.Recorder.Event(g.Gate, corev1.EventTypeWarning, EventReasonTargetInvalidated,
+				fmt.Sprintf("Target %s is not validated anymore: %s", condition.Type, condition.Reason))
+		}
+	}
+
+	consecutive := g.Gate.Status.ConsecutiveValidEvaluations
+	if g.Gate.Status.State == gateshv1alpha1.GateStateClosed && consecutive > 0 && consecutive != previousConsecutiveValid {
+		g.Recorder.Event(g.Gate, corev1.EventTypeNormal, EventReasonConsolidationProgress,
+			fmt.Sprintf("%d/%d consecutive valid evaluations to open", consecutive, g.Gate.Spec.Consolidation.Count))
+	}
+	consecutiveInvalid := g.Gate.Status.ConsecutiveInvalidEvaluations
+	if g.Gate.Status.State == gateshv1alpha1.GateStateOpened && consecutiveInvalid > 0 && consecutiveInvalid != previousConsecutiveInvalid {
+		g.Recorder.Event(g.Gate, corev1.EventTypeWarning, EventReasonCloseConsolidationProgress,
+			fmt.Sprintf("%d/%d consecutive invalid evaluations to close", consecutiveInvalid, g.Gate.Spec.CloseConsolidation.Count))
+	}
+}
+
+func (g *GateCommonReconciler) RecordFetchErrorEvent(target *gateshv1alpha1.GateTarget, err error) {
+	if g.Recorder == nil {
+		return
+	}
+	g.Recorder.ThrottledEvent(g.Gate, corev1.EventTypeWarning, EventReasonFetchError,
+		fmt.Sprintf("Unable to fetch the objects of target %s: %s", target.Name, err.Error()))
+}
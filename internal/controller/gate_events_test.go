@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateEvents", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var fakeRecorder *record.FakeRecorder
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeRecorder = record.NewFakeRecorder(100)
+	})
+
+	newReconciler := func(gate *gateshv1alpha1.Gate, objects ...runtime.Object) *GateCommonReconciler {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).WithRuntimeObjects(objects...).Build()
+		return &GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Recorder: NewGateEventRecorder(fakeRecorder)}}
+	}
+
+	recordedEvents := func() []string {
+		events := make([]string, 0)
+		for len(fakeRecorder.Events) > 0 {
+			events = append(events, <-fakeRecorder.Events)
+		}
+		return events
+	}
+
+	It("should record the transitions of the gate and of its targets", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := newReconciler(gate, configMap)
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(recordedEvents()).To(Equal([]string{"Normal Opened Gate opened"}))
+
+		Expect(reconciler.Client.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(recordedEvents()).To(Equal([]string{
+			"Warning Closed Gate closed, failing targets: [Config]",
+			"Warning TargetInvalidated Target Config is not validated anymore: ConditionNotMet",
+		}))
+	})
+
+	It("should record the consolidation progress", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Consolidation: gateshv1alpha1.GateConsolidation{Count: 2},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := newReconciler(gate, configMap)
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(recordedEvents()).To(ContainElement("Normal ConsolidationProgress 1/2 consecutive valid evaluations to open"))
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(recordedEvents()).To(Equal([]string{"Normal Opened Gate opened"}))
+	})
+
+	It("should record the consolidation progress only when it changes", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Consolidation: gateshv1alpha1.GateConsolidation{Count: 1, StableFor: &metav1.Duration{Duration: time.Hour}},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := newReconciler(gate, configMap)
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(recordedEvents()).To(ContainElement("Normal ConsolidationProgress 1/1 consecutive valid evaluations to open"))
+
+		By("waiting for the soak period")
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(recordedEvents()).To(BeEmpty())
+	})
+
+	It("should throttle the repeated fetch errors", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "invalid/api/version",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		reconciler := newReconciler(gate)
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(recordedEvents()).To(Equal([]string{
+			"Warning FetchError Unable to fetch the objects of target Config: invalid ApiVersion invalid/api/version: unexpected GroupVersion string: invalid/api/version",
+			"Normal Closed Gate closed, failing targets: [Config]",
+		}))
+	})
+})
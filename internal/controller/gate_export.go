@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"fmt"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ExportManagedByLabel marks the ConfigMaps created by the exporter, deleted once they don't hold any gate state.
+const ExportManagedByLabel = "app.kubernetes.io/managed-by"
+const ExportManagedByValue = "gate-operator"
+
+// ReconcileExport writes the state of the gate into the ConfigMap of its export, and removes it from the ConfigMap
+// previously used when the export changed or the gate is being deleted.
+func (g *GateCommonReconciler) ReconcileExport() {
+	log := logf.FromContext(g.Context)
+
+	desired := g.GetExportConfigMapReference()
+	current := g.Gate.Status.ExportedConfigMap
+	if current != nil && (desired == nil || *current != *desired) {
+		if err := g.RemoveExportedState(*current); err != nil {
+			log.Error(err, "unable to remove exported state", "configMap", g.GetObjectReferenceKey(*current))
+			g.ShortenRequeueAfter(RetryBaseDelay)
+			return
+		}
+		g.Gate.Status.ExportedConfigMap = nil
+	}
+
+	if desired == nil {
+		g.ExportReleased = true
+		return
+	}
+	if err := g.ExportState(*desired); err != nil {
+		log.Error(err, "unable to export state", "configMap", g.GetObjectReferenceKey(*desired))
+		g.ShortenRequeueAfter(RetryBaseDelay)
+		return
+	}
+	g.Gate.Status.ExportedConfigMap = desired
+}
+
+// GetExportConfigMapReference returns the ConfigMap the state must be exported to, nil if the state must not be
+// exported anymore.
+func (g *GateCommonReconciler) GetExportConfigMapReference() *gateshv1alpha1.GateObjectReference {
+	export := g.Gate.Spec.Export.ConfigMap
+	if export == nil || !g.Gate.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	namespace := g.Gate.Namespace
+	if namespace == "" {
+		namespace = export.Namespace
+	}
+	return &gateshv1alpha1.GateObjectReference{ApiVersion: "v1", Kind: "ConfigMap", Namespace: namespace, Name: export.Name}
+}
+
+func (g *GateCommonReconciler) GetExportKey() string {
+	if g.Gate.Namespace == "" {
+		return fmt.Sprintf("clustergate.%s", g.Gate.Name)
+	}
+	return g.Gate.Name
+}
+
+func (g *GateCommonReconciler) ExportState(reference gateshv1alpha1.GateObjectReference) error {
+	key := g.GetExportKey()
+	var configMap corev1.ConfigMap
+	err := g.Client.Get(g.Context, client.ObjectKey{Namespace: reference.Namespace, Name: reference.Name}, &configMap)
+	if errors.IsNotFound(err) {
+		configMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: reference.Namespace,
+				Name:      reference.Name,
+				Labels:    map[string]string{ExportManagedByLabel: ExportManagedByValue},
+			},
+			Data: map[string]string{key: g.Gate.Status.State},
+		}
+		return g.Client.Create(g.Context, &configMap)
+	} else if err != nil {
+		return err
+	}
+
+	if value, ok := configMap.Data[key]; ok && value == g.Gate.Status.State {
+		return nil
+	}
+	patch := client.MergeFromWithOptions(configMap.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[key] = g.Gate.Status.State
+	return g.Client.Patch(g.Context, &configMap, patch)
+}
+
+// RemoveExportedState removes the state of the gate from the ConfigMap. A ConfigMap created by the exporter is deleted
+// once it doesn't hold any state.
+func (g *GateCommonReconciler) RemoveExportedState(reference gateshv1alpha1.GateObjectReference) error {
+	key := g.GetExportKey()
+	var configMap corev1.ConfigMap
+	err := g.Client.Get(g.Context, client.ObjectKey{Namespace: reference.Namespace, Name: reference.Name}, &configMap)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if _, ok := configMap.Data[key]; !ok {
+		return nil
+	}
+
+	if len(configMap.Data) == 1 && len(configMap.BinaryData) == 0 && configMap.Labels[ExportManagedByLabel] == ExportManagedByValue {
+		return client.IgnoreNotFound(g.Client.Delete(g.Context, &configMap, client.Preconditions{ResourceVersion: &configMap.ResourceVersion}))
+	}
+	patch := client.MergeFromWithOptions(configMap.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	delete(configMap.Data, key)
+	return g.Client.Patch(g.Context, &configMap, patch)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateExport", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	getExportedConfigMap := func(cl client.Client, name string) (*corev1.ConfigMap, error) {
+		result := &corev1.ConfigMap{}
+		err := cl.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, result)
+		return result, err
+	}
+
+	It("should export the state of the gate in the namespace ConfigMap", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Export: gateshv1alpha1.GateExport{ConfigMap: &gateshv1alpha1.GateExportConfigMap{}},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		exported, err := getExportedConfigMap(cl, "gate-states")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exported.Data).To(Equal(map[string]string{"test-gate": gateshv1alpha1.GateStateClosed}))
+		Expect(exported.Labels).To(HaveKeyWithValue(ExportManagedByLabel, ExportManagedByValue))
+		Expect(gate.Status.ExportedConfigMap.Name).To(Equal("gate-states"))
+
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		exported, err = getExportedConfigMap(cl, "gate-states")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exported.Data).To(HaveKeyWithValue("test-gate", gateshv1alpha1.GateStateOpened))
+	})
+
+	It("should keep the states of the other gates", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Export: gateshv1alpha1.GateExport{ConfigMap: &gateshv1alpha1.GateExportConfigMap{}},
+			},
+		}
+
+		shared := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "gate-states", Namespace: "default"},
+			Data:       map[string]string{"other-gate": gateshv1alpha1.GateStateOpened},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, shared).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		exported, err := getExportedConfigMap(cl, "gate-states")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exported.Data).To(HaveKeyWithValue("other-gate", gateshv1alpha1.GateStateOpened))
+		Expect(exported.Data).To(HaveKeyWithValue("test-gate", gateshv1alpha1.GateStateClosed))
+
+		By("deleting the gate")
+		gate.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		exported, err = getExportedConfigMap(cl, "gate-states")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exported.Data).To(Equal(map[string]string{"other-gate": gateshv1alpha1.GateStateOpened}))
+		Expect(gate.Status.ExportedConfigMap).To(BeNil())
+		Expect(reconciler.ExportReleased).To(BeTrue())
+	})
+
+	It("should move the state when the ConfigMap changes and delete the emptied one", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Export: gateshv1alpha1.GateExport{ConfigMap: &gateshv1alpha1.GateExportConfigMap{}},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		gate.Spec.Export.ConfigMap.Name = "test-gate-state"
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		_, err := getExportedConfigMap(cl, "gate-states")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		exported, err := getExportedConfigMap(cl, "test-gate-state")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exported.Data).To(HaveKey("test-gate"))
+	})
+
+	It("should manage the export finalizer of the gate", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Export: gateshv1alpha1.GateExport{ConfigMap: &gateshv1alpha1.GateExportConfigMap{}},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(AddGateFinalizers(gate, &gate.Spec)).To(BeTrue())
+		Expect(gate.Finalizers).To(ContainElement(gateshv1alpha1.GateExportFinalizer))
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.RemoveGateFinalizers(gate)).To(BeFalse())
+
+		gate.Spec.Export.ConfigMap = nil
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.RemoveGateFinalizers(gate)).To(BeTrue())
+		Expect(gate.Finalizers).NotTo(ContainElement(gateshv1alpha1.GateExportFinalizer))
+	})
+})
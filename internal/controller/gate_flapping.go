@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const GateFlappingReason = "Flapping"
+
+// ApplyFlapping counts the transitions of the evaluated state of the gate within the flapping window. The gate is
+// flapping once they exceed the threshold, until no transition happens for the quiet period. With spec.flapping.hold,
+// the gate holds the state it had before the first transition of the window while it's flapping. Only the evaluated
+// states, Opened or Closed, are counted and held: the state set afterward by the timeout is not.
+func (g *GateCommonReconciler) ApplyFlapping(previousState gateshv1alpha1.GateState) {
+	spec := g.Gate.Spec.Flapping
+	status := &g.Gate.Status
+	if spec == nil {
+		status.Flapping = nil
+		meta.RemoveStatusCondition(&status.Conditions, gateshv1alpha1.GateConditionFlapping)
+		return
+	}
+	if status.Flapping == nil {
+		status.Flapping = &gateshv1alpha1.GateFlappingStatus{}
+	}
+	flapping := status.Flapping
+	now := metav1.Now()
+
+	evaluatedState := status.State
+	lastEvaluatedState := flapping.EvaluatedState
+	if lastEvaluatedState == "" && previousState != gateshv1alpha1.GateStateFailed {
+		lastEvaluatedState = previousState
+	}
+	flapping.EvaluatedState = evaluatedState
+	if lastEvaluatedState != "" && evaluatedState != lastEvaluatedState {
+		flapping.Transitions = append(flapping.Transitions, gateshv1alpha1.GateFlappingTransition{Time: now, From: lastEvaluatedState})
+	}
+	g.PruneFlappingTransitions(now.Time)
+
+	if flapping.Since == nil && len(flapping.Transitions) > spec.Threshold {
+		g.StartFlapping(now)
+	} else if flapping.Since != nil && g.GetQuietRemaining(now.Time) <= 0 {
+		g.StopFlapping()
+	}
+
+	g.VerboseLog("Flapping computed",
+		"transitions", len(flapping.Transitions),
+		"threshold", spec.Threshold,
+		"window", spec.Window.Duration.String(),
+		"since", flapping.Since,
+		"heldState", flapping.HeldState,
+		"evaluatedState", evaluatedState,
+	)
+
+	if flapping.Since != nil {
+		// Reconciled again when the quiet period elapses, to stop flapping.
+		g.ShortenRequeueAfter(g.GetQuietRemaining(now.Time))
+	}
+	if flapping.HeldState == "" {
+		return
+	}
+
+	status.State = flapping.HeldState
+	opened, closed := metav1.ConditionTrue, metav1.ConditionFalse
+	if status.State != gateshv1alpha1.GateStateOpened {
+		opened, closed = closed, opened
+	}
+	message := fmt.Sprintf("State held to %s while flapping, evaluated to %s", flapping.HeldState, evaluatedState)
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateStateOpened, Status: opened, Reason: GateFlappingReason, Message: message})
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateStateClosed, Status: closed, Reason: GateFlappingReason, Message: message})
+}
+
+// PruneFlappingTransitions drops the transitions older than the flapping window, and the oldest ones beyond the
+// threshold + 1 transitions needed to detect the flapping.
+func (g *GateCommonReconciler) PruneFlappingTransitions(now time.Time) {
+	flapping := g.Gate.Status.Flapping
+	start := now.Add(-g.Gate.Spec.Flapping.Window.Duration)
+	first := 0
+	for first < len(flapping.Transitions) && flapping.Transitions[first].Time.Time.Before(start) {
+		first++
+	}
+	if overflow := len(flapping.Transitions) - first - (g.Gate.Spec.Flapping.Threshold + 1); overflow > 0 {
+		first += overflow
+	}
+	if first == len(flapping.Transitions) {
+		flapping.Transitions = nil
+		return
+	}
+	flapping.Transitions = flapping.Transitions[first:]
+}
+
+func (g *GateCommonReconciler) GetQuietRemaining(now time.Time) time.Duration {
+	flapping := g.Gate.Status.Flapping
+	last := flapping.Since.Time
+	if count := len(flapping.Transitions); count > 0 && flapping.Transitions[count-1].Time.After(last) {
+		last = flapping.Transitions[count-1].Time.Time
+	}
+	return last.Add(g.Gate.Spec.Flapping.QuietPeriod.Duration).Sub(now)
+}
+
+// StartFlapping sets the Flapping condition and records a warning. With spec.flapping.hold, the gate holds the state
+// it had before the first transition of the window.
+func (g *GateCommonReconciler) StartFlapping(now metav1.Time) {
+	spec := g.Gate.Spec.Flapping
+	flapping := g.Gate.Status.Flapping
+	flapping.Since = &now
+	if spec.Hold {
+		flapping.HeldState = flapping.Transitions[0].From
+	}
+
+	message := fmt.Sprintf("%d transitions within %s", len(flapping.Transitions), spec.Window.Duration)
+	if flapping.HeldState != "" {
+		message += fmt.Sprintf(", state held to %s", flapping.HeldState)
+	}
+	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{
+		Type:               gateshv1alpha1.GateConditionFlapping,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: g.Gate.Generation,
+		Reason:             "TooManyTransitions",
+		Message:            message,
+	})
+	logf.FromContext(g.Context).Info(fmt.Sprintf("%s %s is flapping", g.Gate.Kind, g.Gate.Name), "transitions", len(flapping.Transitions), "heldState", flapping.HeldState)
+	if g.Recorder != nil {
+		g.Recorder.Event(g.Gate, corev1.EventTypeWarning, EventReasonFlapping, fmt.Sprintf("%s is flapping: %s", g.GetGateKind(), message))
+	}
+}
+
+func (g *GateCommonReconciler) StopFlapping() {
+	flapping := g.Gate.Status.Flapping
+	flapping.Since = nil
+	flapping.HeldState = ""
+	meta.RemoveStatusCondition(&g.Gate.Status.Conditions, gateshv1alpha1.GateConditionFlapping)
+	logf.FromContext(g.Context).Info(fmt.Sprintf("%s %s is not flapping anymore", g.Gate.Kind, g.Gate.Name))
+	if g.Recorder != nil {
+		g.Recorder.Event(g.Gate, corev1.EventTypeNormal, EventReasonFlappingCleared,
+			fmt.Sprintf("%s is not flapping anymore, no transition within %s", g.GetGateKind(), g.Gate.Spec.Flapping.QuietPeriod.Duration))
+	}
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateFlapping", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	// toggle creates the ConfigMap targeted by the gate if it's missing, deletes it otherwise, and reconciles the gate.
+	toggle := func(cl client.Client, reconciler *GateCommonReconciler) {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(configMap), configMap); err == nil {
+			Expect(cl.Delete(ctx, configMap)).To(Succeed())
+		} else {
+			Expect(cl.Create(ctx, configMap)).To(Succeed())
+		}
+		Expect(reconciler.Reconcile()).To(Succeed())
+	}
+
+	// elapse moves the flapping of the gate back in time by the given duration.
+	elapse := func(gate *gateshv1alpha1.Gate, duration time.Duration) {
+		flapping := gate.Status.Flapping
+		since := metav1.NewTime(flapping.Since.Add(-duration))
+		flapping.Since = &since
+		for idx := range flapping.Transitions {
+			flapping.Transitions[idx].Time = metav1.NewTime(flapping.Transitions[idx].Time.Add(-duration))
+		}
+	}
+
+	It("should detect the gate flapping and clear it after the quiet period", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Flapping: &gateshv1alpha1.GateFlapping{
+					Threshold:   2,
+					Window:      &metav1.Duration{Duration: time.Hour},
+					QuietPeriod: &metav1.Duration{Duration: time.Minute},
+				},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		recorder := record.NewFakeRecorder(20)
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Recorder: NewGateEventRecorder(recorder)}}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Flapping.Transitions).To(BeEmpty())
+
+		By("counting the transitions up to the threshold")
+		toggle(cl, &reconciler)
+		toggle(cl, &reconciler)
+		Expect(gate.Status.Flapping.Transitions).To(HaveLen(2))
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionFlapping)).To(BeNil())
+
+		By("flapping once the threshold is exceeded")
+		toggle(cl, &reconciler)
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(meta.IsStatusConditionTrue(gate.Status.Conditions, gateshv1alpha1.GateConditionFlapping)).To(BeTrue())
+		Expect(gate.Status.Flapping.Since).NotTo(BeNil())
+		Expect(reconciler.RequeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring(EventReasonFlapping)))
+
+		By("clearing the flapping after the quiet period")
+		elapse(gate, 2*time.Minute)
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionFlapping)).To(BeNil())
+		Expect(gate.Status.Flapping.Since).To(BeNil())
+		Expect(gate.Status.Flapping.Transitions).To(HaveLen(3))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring(EventReasonFlappingCleared)))
+	})
+
+	It("should hold the last stable state while flapping", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Flapping: &gateshv1alpha1.GateFlapping{
+					Threshold:   2,
+					Window:      &metav1.Duration{Duration: time.Hour},
+					QuietPeriod: &metav1.Duration{Duration: time.Minute},
+					Hold:        true,
+				},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		toggle(cl, &reconciler)
+		toggle(cl, &reconciler)
+		Expect(gate.Status.History).To(HaveLen(3))
+
+		By("holding the state the gate had before the first transition of the window")
+		toggle(cl, &reconciler)
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.Flapping.HeldState).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.Flapping.EvaluatedState).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened).Reason).To(Equal(GateFlappingReason))
+		Expect(gate.Status.History).To(HaveLen(3))
+
+		By("counting the transitions of the evaluated state while held")
+		toggle(cl, &reconciler)
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.Flapping.Transitions).To(HaveLen(3))
+		Expect(gate.Status.Flapping.Transitions[2].From).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		By("releasing the state after the quiet period")
+		toggle(cl, &reconciler)
+		elapse(gate, 2*time.Minute)
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.Flapping.HeldState).To(BeEmpty())
+		Expect(gate.Status.History).To(HaveLen(4))
+	})
+
+	It("should not count the timeout as a transition", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-gate",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+			},
+			Spec: gateshv1alpha1.GateSpec{
+				Timeout:       &metav1.Duration{Duration: time.Hour},
+				TimeoutPolicy: gateshv1alpha1.GateTimeoutPolicySlow,
+				Flapping: &gateshv1alpha1.GateFlapping{
+					Threshold:   2,
+					Window:      &metav1.Duration{Duration: time.Hour},
+					QuietPeriod: &metav1.Duration{Duration: time.Minute},
+				},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+		Expect(gate.Status.Flapping.EvaluatedState).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.Flapping.Transitions).To(BeEmpty())
+	})
+
+	It("should fail on timeout while holding the state", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Timeout:       &metav1.Duration{Duration: time.Hour},
+				TimeoutPolicy: gateshv1alpha1.GateTimeoutPolicySlow,
+				Flapping: &gateshv1alpha1.GateFlapping{
+					Threshold:   2,
+					Window:      &metav1.Duration{Duration: time.Hour},
+					QuietPeriod: &metav1.Duration{Duration: time.Minute},
+					Hold:        true,
+				},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		toggle(cl, &reconciler)
+		toggle(cl, &reconciler)
+		toggle(cl, &reconciler)
+		Expect(gate.Status.Flapping.HeldState).To(Equal(gateshv1alpha1.GateStateClosed))
+
+		By("timing out while the state is held")
+		gate.Status.TimeoutStartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		gate.Status.LastOpenedTime = &metav1.Time{Time: time.Now().Add(-3 * time.Hour)}
+		toggle(cl, &reconciler)
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+		Expect(meta.IsStatusConditionTrue(gate.Status.Conditions, gateshv1alpha1.GateConditionTimedOut)).To(BeTrue())
+		Expect(gate.Status.Flapping.HeldState).To(Equal(gateshv1alpha1.GateStateClosed))
+
+		By("counting the transitions of the evaluated state only")
+		toggle(cl, &reconciler)
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+		Expect(gate.Status.Flapping.EvaluatedState).To(Equal(gateshv1alpha1.GateStateOpened))
+		for _, transition := range gate.Status.Flapping.Transitions {
+			Expect(transition.From).To(BeElementOf(gateshv1alpha1.GateStateOpened, gateshv1alpha1.GateStateClosed))
+		}
+	})
+})
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"maps"
+	"slices"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// RecordHistory appends the transition of the gate to its history, dropping the oldest entries beyond
+// GateHistoryMaxEntries, and updates the opening times.
+func (g *GateCommonReconciler) RecordHistory(previousState gateshv1alpha1.GateState) {
+	transitionTime := metav1.Now()
+	reason := ""
+	if condition := meta.FindStatusCondition(g.Gate.Status.Conditions, gateshv1alpha1.GateStateOpened); condition != nil {
+		reason = condition.Reason
+		// The Opened condition doesn't flip between Closed and Failed.
+		if (previousState == gateshv1alpha1.GateStateOpened) != (g.Gate.Status.State == gateshv1alpha1.GateStateOpened) {
+			transitionTime = condition.LastTransitionTime
+		}
+	}
+
+	entry := gateshv1alpha1.GateHistoryEntry{
+		Time:   transitionTime,
+		From:   previousState,
+		To:     g.Gate.Status.State,
+		Reason: reason,
+	}
+	if failing := g.GetFailingTargets(); len(failing) > 0 {
+		entry.FailingTargets = failing
+	}
+	g.AppendHistory(entry)
+
+	if g.Gate.Status.State == gateshv1alpha1.GateStateOpened {
+		if g.Gate.Status.FirstOpenedTime == nil {
+			g.Gate.Status.FirstOpenedTime = &transitionTime
+		}
+		g.Gate.Status.LastOpenedTime = &transitionTime
+	}
+}
+
+func (g *GateCommonReconciler) AppendHistory(entry gateshv1alpha1.GateHistoryEntry) {
+	g.Gate.Status.History = append(g.Gate.Status.History, entry)
+	if overflow := len(g.Gate.Status.History) - gateshv1alpha1.GateHistoryMaxEntries; overflow > 0 {
+		g.Gate.Status.History = g.Gate.Status.History[overflow:]
+	}
+}
+
+// StatusUpdateIgnoredPredicate filters out the updates of the gates which only change their status. Each evaluation
+// writes its time in the status, so without it every evaluation would trigger the next one right away instead of
+// waiting for the requeue period. The changes of the spec, the labels, the annotations, the finalizers and the
+// deletion of the gates still trigger a reconciliation.
+func StatusUpdateIgnoredPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return true
+			}
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				!maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+				!maps.Equal(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()) ||
+				!slices.Equal(e.ObjectOld.GetFinalizers(), e.ObjectNew.GetFinalizers()) ||
+				!equality.Semantic.DeepEqual(e.ObjectOld.GetOwnerReferences(), e.ObjectNew.GetOwnerReferences()) ||
+				!e.ObjectOld.GetDeletionTimestamp().Equal(e.ObjectNew.GetDeletionTimestamp())
+		},
+	}
+}
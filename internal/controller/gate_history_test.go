@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("GateHistory", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should record the transitions and the opening times", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.LastEvaluationTime).NotTo(BeNil())
+		Expect(gate.Status.FirstOpenedTime).To(BeNil())
+		Expect(gate.Status.History).To(HaveLen(1))
+		Expect(gate.Status.History[0].From).To(BeEmpty())
+		Expect(gate.Status.History[0].To).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.History[0].FailingTargets).To(Equal([]string{"Config"}))
+		Expect(gate.Status.History[0].Reason).To(Equal("GateConditionNotMet"))
+
+		By("keeping the history unchanged without transition")
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.History).To(HaveLen(1))
+
+		By("opening the gate")
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.History).To(HaveLen(2))
+		Expect(gate.Status.History[1].From).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.History[1].To).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.History[1].FailingTargets).To(BeEmpty())
+		Expect(gate.Status.FirstOpenedTime).NotTo(BeNil())
+		Expect(gate.Status.LastOpenedTime).To(Equal(gate.Status.FirstOpenedTime))
+		firstOpenedTime := *gate.Status.FirstOpenedTime
+
+		By("closing and opening the gate again")
+		Expect(cl.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		configMap.ResourceVersion = ""
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.History).To(HaveLen(4))
+		Expect(*gate.Status.FirstOpenedTime).To(Equal(firstOpenedTime))
+		Expect(gate.Status.LastOpenedTime.Time).To(Equal(gate.Status.History[3].Time.Time))
+	})
+
+	It("should record the time of a transition from Closed to Failed", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Timeout: &metav1.Duration{Duration: time.Hour},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+
+		By("timing out long after the gate closed")
+		gate.Status.TimeoutStartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened).LastTransitionTime = *gate.Status.TimeoutStartTime
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.History).To(HaveLen(2))
+		Expect(gate.Status.History[1].To).To(Equal(gateshv1alpha1.GateStateFailed))
+		Expect(gate.Status.History[1].Time.Time).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	It("should keep a bounded history", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+			Status: gateshv1alpha1.GateStatus{State: gateshv1alpha1.GateStateOpened},
+		}
+
+		for range gateshv1alpha1.GateHistoryMaxEntries {
+			gate.Status.History = append(gate.Status.History, gateshv1alpha1.GateHistoryEntry{
+				From: gateshv1alpha1.GateStateClosed,
+				To:   gateshv1alpha1.GateStateOpened,
+			})
+		}
+		gate.Status.History[0].Reason = "Oldest"
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.History).To(HaveLen(gateshv1alpha1.GateHistoryMaxEntries))
+		Expect(gate.Status.History[0].Reason).To(BeEmpty())
+		Expect(gate.Status.History[gateshv1alpha1.GateHistoryMaxEntries-1].To).To(Equal(gateshv1alpha1.GateStateClosed))
+	})
+
+	It("should not reconcile the updates of the status of the gates", func() {
+		gate := &gateshv1alpha1.Gate{ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", Generation: 1}}
+
+		reconciled := func(update func(gate *gateshv1alpha1.Gate)) bool {
+			updated := gate.DeepCopy()
+			update(updated)
+			return StatusUpdateIgnoredPredicate().Update(event.UpdateEvent{ObjectOld: gate, ObjectNew: updated})
+		}
+		Expect(reconciled(func(gate *gateshv1alpha1.Gate) {
+			now := metav1.Now()
+			gate.Status.LastEvaluationTime = &now
+		})).To(BeFalse())
+		Expect(reconciled(func(gate *gateshv1alpha1.Gate) { gate.Generation = 2 })).To(BeTrue())
+		Expect(reconciled(func(gate *gateshv1alpha1.Gate) {
+			gate.Annotations = map[string]string{gateshv1alpha1.GateReevaluateAnnotation: "1"}
+		})).To(BeTrue())
+		Expect(reconciled(func(gate *gateshv1alpha1.Gate) {
+			now := metav1.Now()
+			gate.DeletionTimestamp = &now
+		})).To(BeTrue())
+	})
+})
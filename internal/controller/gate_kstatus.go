@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpdateKstatusConditions maintains the Ready, Reconciling and Stalled conditions of the observed generation
+// following the kstatus conventions, for the GitOps tools to wait for the gate to open without custom health checks.
+// Reconciling and Stalled are abnormal-true conditions: they are removed instead of being set to False.
+func (g *GateCommonReconciler) UpdateKstatusConditions() {
+	status := &g.Gate.Status
+
+	opened := meta.FindStatusCondition(status.Conditions, gateshv1alpha1.GateStateOpened)
+	ready := metav1.Condition{
+		Type:               gateshv1alpha1.GateConditionReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: status.ObservedGeneration,
+		Reason:             "NotEvaluated",
+	}
+	if opened != nil {
+		ready.Status = opened.Status
+		ready.Reason = opened.Reason
+		ready.Message = opened.Message
+	}
+	meta.SetStatusCondition(&status.Conditions, ready)
+
+	var unreachable []string
+	for _, condition := range status.TargetConditions {
+		if condition.Reason == TargetReasonErrorWhileFetching {
+			unreachable = append(unreachable, condition.Type)
+		}
+	}
+
+	switch {
+	case status.State == gateshv1alpha1.GateStateOpened:
+		meta.RemoveStatusCondition(&status.Conditions, gateshv1alpha1.GateConditionReconciling)
+		meta.RemoveStatusCondition(&status.Conditions, gateshv1alpha1.GateConditionStalled)
+	case status.State == gateshv1alpha1.GateStateFailed:
+		meta.RemoveStatusCondition(&status.Conditions, gateshv1alpha1.GateConditionReconciling)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               gateshv1alpha1.GateConditionStalled,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: status.ObservedGeneration,
+			Reason:             ready.Reason,
+			Message:            ready.Message,
+		})
+	case len(unreachable) > 0:
+		meta.RemoveStatusCondition(&status.Conditions, gateshv1alpha1.GateConditionReconciling)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               gateshv1alpha1.GateConditionStalled,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: status.ObservedGeneration,
+			Reason:             TargetReasonErrorWhileFetching,
+			Message:            fmt.Sprintf("not able to fetch the objects of the targets %s", strings.Join(unreachable, ", ")),
+		})
+	default:
+		meta.RemoveStatusCondition(&status.Conditions, gateshv1alpha1.GateConditionStalled)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               gateshv1alpha1.GateConditionReconciling,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: status.ObservedGeneration,
+			Reason:             ready.Reason,
+			Message:            ready.Message,
+		})
+	}
+}
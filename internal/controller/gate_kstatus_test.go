@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateKstatus", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should report the gate as reconciling until it opens", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", Generation: 3},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.ObservedGeneration).To(Equal(int64(3)))
+		ready := meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("GateConditionNotMet"))
+		Expect(ready.ObservedGeneration).To(Equal(int64(3)))
+		Expect(meta.IsStatusConditionTrue(gate.Status.Conditions, gateshv1alpha1.GateConditionReconciling)).To(BeTrue())
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionStalled)).To(BeNil())
+
+		By("opening the gate")
+		Expect(cl.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}})).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(gate.Status.Conditions, gateshv1alpha1.GateConditionReady)).To(BeTrue())
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionReconciling)).To(BeNil())
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionStalled)).To(BeNil())
+	})
+
+	It("should report the gate as stalled when the objects cannot be fetched", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "invalid/api/version",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(gate.Status.Conditions, gateshv1alpha1.GateConditionReady)).To(BeTrue())
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionReconciling)).To(BeNil())
+		stalled := meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionStalled)
+		Expect(stalled).NotTo(BeNil())
+		Expect(stalled.Status).To(Equal(metav1.ConditionTrue))
+		Expect(stalled.Reason).To(Equal(TargetReasonErrorWhileFetching))
+		Expect(stalled.Message).To(ContainSubstring("Config"))
+	})
+})
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	GateLatchResetReason = "LatchReset"
+	GateLatchedReason    = "Latched"
+)
+
+// Latch latches the gate once it opened, if the spec requires it. The targets of a latched gate are not evaluated
+// anymore, so the gate isn't requeued unless its protected objects must be watched.
+func (g *GateCommonReconciler) Latch() {
+	if !g.Gate.Spec.Latch || g.Gate.Status.State != gateshv1alpha1.GateStateOpened {
+		return
+	}
+	now := metav1.Now()
+	g.Gate.Status.LatchedAt = &now
+	g.RequeueAfter = g.GetLatchedRequeueAfter()
+	logf.FromContext(g.Context).Info(fmt.Sprintf("%s %s latched opened", g.Gate.Kind, g.Gate.Name))
+}
+
+// HandleLatchReset unlatches the gate when the gate.sh/reset-latch annotation changed, recording the reset in the
+// history, or when the spec doesn't require the latch anymore.
+func (g *GateCommonReconciler) HandleLatchReset() {
+	if !g.Gate.Spec.Latch {
+		g.Gate.Status.LatchedAt = nil
+	}
+
+	value := g.Gate.GetAnnotations()[gateshv1alpha1.GateResetLatchAnnotation]
+	if value == "" || value == g.Gate.Status.LastHandledLatchReset {
+		return
+	}
+	g.Gate.Status.LastHandledLatchReset = value
+	if g.Gate.Status.LatchedAt == nil {
+		return
+	}
+
+	logf.FromContext(g.Context).Info(fmt.Sprintf("%s %s latch reset", g.Gate.Kind, g.Gate.Name), "latchedAt", g.Gate.Status.LatchedAt)
+	g.Gate.Status.LatchedAt = nil
+	g.AppendHistory(gateshv1alpha1.GateHistoryEntry{
+		Time:   metav1.Now(),
+		From:   g.Gate.Status.State,
+		To:     g.Gate.Status.State,
+		Reason: GateLatchResetReason,
+	})
+}
+
+// ReconcileWithoutEvaluation reconciles a latched or stopped gate without evaluating its targets: its state is kept,
+// while the pending actions, notifications, protection and export are still reconciled.
+func (g *GateCommonReconciler) ReconcileWithoutEvaluation(requeueAfter time.Duration) {
+	g.RequeueAfter = requeueAfter
+	g.Gate.Status.ObservedGeneration = g.Gate.Generation
+	g.UpdateKstatusConditions()
+	g.RecordStateMetrics()
+	g.ExecuteActions()
+	g.DeliverNotifications()
+	g.ReconcileProtection()
+	g.ReconcileExport()
+}
+
+// GetLatchedRequeueAfter returns the delay between two reconciliations of a latched gate. Only the protected objects
+// being deleted need to be watched.
+func (g *GateCommonReconciler) GetLatchedRequeueAfter() time.Duration {
+	if len(g.Gate.Spec.Protect) > 0 {
+		return g.Gate.Spec.EvaluationPeriod.Duration
+	}
+	return 0
+}
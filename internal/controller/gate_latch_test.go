@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateLatch", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should keep the gate opened once latched", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Latch: true,
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		By("not latching a closed gate")
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.LatchedAt).To(BeNil())
+
+		By("latching the gate once opened")
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.LatchedAt).NotTo(BeNil())
+		Expect(reconciler.RequeueAfter).To(BeZero())
+		lastEvaluationTime := gate.Status.LastEvaluationTime
+
+		By("keeping the gate opened without evaluating its targets")
+		Expect(cl.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.LastEvaluationTime).To(Equal(lastEvaluationTime))
+		Expect(reconciler.RequeueAfter).To(BeZero())
+		Expect(gate.Status.History).To(HaveLen(2))
+	})
+
+	It("should reset the latch when the annotation changes", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Latch: true,
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.LatchedAt).NotTo(BeNil())
+
+		By("evaluating the gate again after the reset")
+		Expect(cl.Delete(ctx, configMap)).To(Succeed())
+		gate.Annotations = map[string]string{gateshv1alpha1.GateResetLatchAnnotation: "2025-01-01T00:00:00Z"}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.LastHandledLatchReset).To(Equal("2025-01-01T00:00:00Z"))
+		Expect(gate.Status.LatchedAt).To(BeNil())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.History).To(HaveLen(3))
+		Expect(gate.Status.History[1].Reason).To(Equal(GateLatchResetReason))
+		Expect(gate.Status.History[1].From).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.History[2].To).To(Equal(gateshv1alpha1.GateStateClosed))
+
+		By("not resetting the latch again with the same annotation")
+		configMap.ResourceVersion = ""
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.LatchedAt).NotTo(BeNil())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.LatchedAt).NotTo(BeNil())
+		Expect(gate.Status.History).To(HaveLen(4))
+	})
+
+	It("should keep polling a latched gate protecting objects", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Latch: true,
+				Protect: []gateshv1alpha1.GateProtection{
+					{Selector: gateshv1alpha1.GateTargetSelector{ApiVersion: "v1", Kind: "ConfigMap", Name: "config"}},
+				},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.LatchedAt).NotTo(BeNil())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.RequeueAfter).To(Equal(gate.Spec.EvaluationPeriod.Duration))
+	})
+})
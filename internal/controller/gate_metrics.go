@@ -0,0 +1,46 @@
+package controller
+
+import (
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/metrics"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (g *GateCommonReconciler) GetMetricsLabels() metrics.GateLabels {
+	return metrics.GateLabels{Kind: g.GetGateKind(), Namespace: g.Gate.Namespace, Name: g.Gate.Name}
+}
+
+func (g *GateCommonReconciler) RecordStateMetrics() {
+	targets := make(map[string]bool, len(g.Gate.Status.TargetConditions))
+	for _, condition := range g.Gate.Status.TargetConditions {
+		targets[condition.Type] = condition.Status == metav1.ConditionTrue
+	}
+
+	metrics.RecordGateState(g.GetMetricsLabels(), g.Gate.Status.State, targets, g.GetLastTransitionTime().Time)
+}
+
+// GetLastTransitionTime returns the time of the last state change of the gate, recorded by its history. The Opened
+// condition doesn't flip between Closed and Failed: it's only used without history.
+func (g *GateCommonReconciler) GetLastTransitionTime() metav1.Time {
+	if history := g.Gate.Status.History; len(history) > 0 {
+		return history[len(history)-1].Time
+	}
+	if condition := meta.FindStatusCondition(g.Gate.Status.Conditions, gateshv1alpha1.GateStateOpened); condition != nil {
+		return condition.LastTransitionTime
+	}
+	return metav1.Time{}
+}
+
+// GetFetchErrorReason returns the reason of a fetch error used in the metrics: the API status reason, NoKindMatch
+// when the kind is not served by the API, or Invalid for the errors of the gate spec.
+func GetFetchErrorReason(err error) string {
+	if reason := errors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+	if meta.IsNoMatchError(err) {
+		return "NoKindMatch"
+	}
+	return "Invalid"
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateMetrics", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		DeferCleanup(metrics.DeleteGate, metrics.GateLabels{Kind: "Gate", Namespace: "default", Name: "metrics-gate"})
+	})
+
+	It("should record the state, the targets and the transitions of the gate", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "metrics-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(testutil.ToFloat64(metrics.GateState.WithLabelValues("Gate", "default", "metrics-gate", "Closed"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.TargetStatus.WithLabelValues("Gate", "default", "metrics-gate", "Config"))).To(Equal(0.0))
+
+		Expect(cl.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}})).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(testutil.ToFloat64(metrics.GateState.WithLabelValues("Gate", "default", "metrics-gate", "Opened"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.TargetStatus.WithLabelValues("Gate", "default", "metrics-gate", "Config"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.TransitionsTotal.WithLabelValues("Gate", "default", "metrics-gate", "Opened"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics.ObjectsEvaluatedTotal.WithLabelValues("Gate", "default", "metrics-gate", "Config"))).To(Equal(1.0))
+	})
+
+	It("should record the time of the transitions between closed and failed", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "metrics-gate", Namespace: "default", CreationTimestamp: metav1.Now()},
+			Spec: gateshv1alpha1.GateSpec{
+				Timeout: &metav1.Duration{Duration: 10 * time.Minute},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+
+		By("failing the gate closed for an hour")
+		anHourAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+		gate.Status.History[0].Time = anHourAgo
+		meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened).LastTransitionTime = anHourAgo
+		gate.Status.TimeoutStartTime = &anHourAgo
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+
+		transitionTime, ok := metrics.LastTransitions.Get(reconciler.GetMetricsLabels())
+		Expect(ok).To(BeTrue())
+		Expect(time.Since(transitionTime)).To(BeNumerically("<", time.Minute))
+	})
+
+	It("should count the fetch errors by reason", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "metrics-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "invalid/api/version",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(testutil.ToFloat64(metrics.FetchErrorsTotal.WithLabelValues("Gate", "default", "metrics-gate", "Config", "Invalid"))).To(Equal(1.0))
+	})
+
+	It("should use the API status reason of the fetch errors", func() {
+		err := fmt.Errorf("failed to list objects: %w", errors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", nil))
+		Expect(GetFetchErrorReason(err)).To(Equal("Forbidden"))
+	})
+})
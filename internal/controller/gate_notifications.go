@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/notifier"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// NotificationMaxAttempts is the number of delivery attempts after which a notification is given up.
+var NotificationMaxAttempts = 10
+
+// NotificationTimeout bounds each delivery attempt, so that a slow sink doesn't hold the reconciliation. A timed out
+// delivery is retried like a failed one.
+var NotificationTimeout = 3 * time.Second
+
+// StartNotifications replaces the notifications status with the notifications of the transition between the given
+// states, numbered by the next notification sequence. The notifications are then delivered by DeliverNotifications
+// until they succeed.
+func (g *GateCommonReconciler) StartNotifications(state gateshv1alpha1.GateState, previousState gateshv1alpha1.GateState) {
+	if g.Notifier == nil || len(g.Gate.Spec.Notifications.Channels) == 0 {
+		return
+	}
+	on := g.Gate.Spec.Notifications.On
+	if len(on) > 0 && !slices.Contains(on, state) {
+		return
+	}
+
+	now := metav1.Now()
+	statuses := make([]gateshv1alpha1.GateNotificationStatus, 0, len(g.Gate.Spec.Notifications.Channels))
+	for _, channel := range g.Gate.Spec.Notifications.Channels {
+		statuses = append(statuses, gateshv1alpha1.GateNotificationStatus{
+			Channel:        channel,
+			State:          state,
+			PreviousState:  previousState,
+			Result:         gateshv1alpha1.GateNotificationResultPending,
+			TransitionTime: now,
+		})
+	}
+	g.Gate.Status.Notifications = statuses
+	g.Gate.Status.NotificationSequence += 1
+	g.NotificationsStarted = true
+}
+
+// DeliverNotifications sends the pending notifications of the last transition. Failed deliveries are retried with an
+// exponential backoff until NotificationMaxAttempts is reached, delivered ones are never sent again.
+func (g *GateCommonReconciler) DeliverNotifications() {
+	if g.Notifier == nil {
+		return
+	}
+	if g.NotificationsStarted {
+		// Delivered once the status recording the transition is persisted, so that the transition can't be notified
+		// from a stale status.
+		g.ShortenRequeueAfter(NotificationsStartDelay)
+		return
+	}
+	log := logf.FromContext(g.Context)
+
+	for idx := range g.Gate.Status.Notifications {
+		notificationStatus := &g.Gate.Status.Notifications[idx]
+		if notificationStatus.Result == gateshv1alpha1.GateNotificationResultSucceeded ||
+			notificationStatus.Attempts >= NotificationMaxAttempts {
+			continue
+		}
+
+		if notificationStatus.Result == gateshv1alpha1.GateNotificationResultFailed && notificationStatus.LastAttemptTime != nil {
+			nextAttempt := notificationStatus.LastAttemptTime.Add(g.GetRetryDelay(notificationStatus.Attempts))
+			if wait := time.Until(nextAttempt); wait > 0 {
+				g.ShortenRequeueAfter(wait)
+				continue
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(g.Context, NotificationTimeout)
+		err := g.Notifier.Notify(ctx, notificationStatus.Channel, g.BuildNotification(notificationStatus))
+		cancel()
+		now := metav1.Now()
+		notificationStatus.Attempts += 1
+		notificationStatus.LastAttemptTime = &now
+		if err != nil {
+			log.Error(err, "unable to deliver notification", "channel", notificationStatus.Channel, "state", notificationStatus.State)
+			notificationStatus.Result = gateshv1alpha1.GateNotificationResultFailed
+			notificationStatus.Message = err.Error()
+			if notificationStatus.Attempts < NotificationMaxAttempts {
+				g.ShortenRequeueAfter(g.GetRetryDelay(notificationStatus.Attempts))
+			}
+			continue
+		}
+		notificationStatus.Result = gateshv1alpha1.GateNotificationResultSucceeded
+		notificationStatus.Message = fmt.Sprintf("notification delivered after %d attempt(s)", notificationStatus.Attempts)
+	}
+}
+
+// BuildNotification returns the notification of the transition recorded by the status. Its ID is built from the
+// persisted notification sequence: it's stable across the retries and the replays of the transition, so the receivers
+// can deduplicate the deliveries.
+func (g *GateCommonReconciler) BuildNotification(notificationStatus *gateshv1alpha1.GateNotificationStatus) notifier.Notification {
+	return notifier.Notification{
+		ID:               fmt.Sprintf("%s-%d", g.Gate.UID, g.Gate.Status.NotificationSequence),
+		Kind:             g.GetGateKind(),
+		Namespace:        g.Gate.Namespace,
+		Name:             g.Gate.Name,
+		State:            notificationStatus.State,
+		PreviousState:    notificationStatus.PreviousState,
+		TransitionTime:   notificationStatus.TransitionTime.Time,
+		TargetConditions: g.Gate.Status.TargetConditions,
+	}
+}
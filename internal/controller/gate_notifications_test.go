@@ -0,0 +1,377 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/notifier"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateNotifications", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var server *httptest.Server
+	var notificationIds []string
+	var responseStatus int
+	var responseDelay time.Duration
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		notificationIds = nil
+		responseStatus = http.StatusOK
+		responseDelay = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(responseDelay)
+			notificationIds = append(notificationIds, r.Header.Get(notifier.NotificationIdHeader))
+			w.WriteHeader(responseStatus)
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	newReconciler := func(gate *gateshv1alpha1.Gate, objects ...runtime.Object) *GateCommonReconciler {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).WithRuntimeObjects(objects...).Build()
+		return &GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Notifier: notifier.NewGateNotifier(cl)}}
+	}
+
+	It("should notify the channels once per transition", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Notifications: gateshv1alpha1.GateNotifications{Channels: []string{"webhook"}},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		channel := &gateshv1alpha1.NotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+			Spec: gateshv1alpha1.NotificationChannelSpec{
+				Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+					NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				},
+			},
+		}
+
+		reconciler := newReconciler(gate, configMap, channel)
+
+		By("delivering the notifications once the transition is persisted")
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(notificationIds).To(BeEmpty())
+		Expect(gate.Status.Notifications[0].Result).To(Equal(gateshv1alpha1.GateNotificationResultPending))
+		Expect(reconciler.RequeueAfter).To(Equal(NotificationsStartDelay))
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(notificationIds).To(HaveLen(1))
+		Expect(gate.Status.Notifications).To(HaveLen(1))
+		Expect(gate.Status.Notifications[0].Channel).To(Equal("webhook"))
+		Expect(gate.Status.Notifications[0].State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.Notifications[0].Result).To(Equal(gateshv1alpha1.GateNotificationResultSucceeded))
+		Expect(gate.Status.Notifications[0].Attempts).To(Equal(1))
+	})
+
+	It("should not notify when the gate is created closed", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Notifications: gateshv1alpha1.GateNotifications{Channels: []string{"webhook"}},
+			},
+		}
+		channel := &gateshv1alpha1.NotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+			Spec: gateshv1alpha1.NotificationChannelSpec{
+				Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+					NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				},
+			},
+		}
+
+		reconciler := newReconciler(gate, channel)
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.Notifications).To(BeEmpty())
+		Expect(notificationIds).To(BeEmpty())
+	})
+
+	It("should only notify the transitions to the selected states", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Notifications: gateshv1alpha1.GateNotifications{
+					Channels: []string{"webhook"},
+					On:       []gateshv1alpha1.GateState{gateshv1alpha1.GateStateClosed},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		channel := &gateshv1alpha1.NotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+			Spec: gateshv1alpha1.NotificationChannelSpec{
+				Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+					NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				},
+			},
+		}
+
+		reconciler := newReconciler(gate, configMap, channel)
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(notificationIds).To(BeEmpty())
+
+		Expect(reconciler.Client.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(notificationIds).To(HaveLen(1))
+		Expect(gate.Status.Notifications[0].State).To(Equal(gateshv1alpha1.GateStateClosed))
+	})
+
+	It("should notify the state the gate transitioned from", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Notifications: gateshv1alpha1.GateNotifications{Channels: []string{"webhook"}},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		channel := &gateshv1alpha1.NotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+			Spec: gateshv1alpha1.NotificationChannelSpec{
+				Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+					NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				},
+			},
+		}
+
+		reconciler := newReconciler(gate, configMap, channel)
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Notifications[0].PreviousState).To(BeEmpty())
+
+		Expect(reconciler.Client.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Notifications[0].PreviousState).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		notification := reconciler.BuildNotification(&gateshv1alpha1.GateNotificationStatus{
+			State:         gateshv1alpha1.GateStateFailed,
+			PreviousState: gateshv1alpha1.GateStateClosed,
+		})
+		Expect(notification.PreviousState).To(Equal(gateshv1alpha1.GateStateClosed))
+	})
+
+	It("should bound each delivery attempt", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Notifications: gateshv1alpha1.GateNotifications{Channels: []string{"webhook"}},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		channel := &gateshv1alpha1.NotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+			Spec: gateshv1alpha1.NotificationChannelSpec{
+				Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+					NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				},
+			},
+		}
+
+		timeout := NotificationTimeout
+		NotificationTimeout = 50 * time.Millisecond
+		DeferCleanup(func() { NotificationTimeout = timeout })
+		responseDelay = 500 * time.Millisecond
+		reconciler := newReconciler(gate, configMap, channel)
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		start := time.Now()
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(time.Since(start)).To(BeNumerically("<", responseDelay))
+		Expect(gate.Status.Notifications[0].Result).To(Equal(gateshv1alpha1.GateNotificationResultFailed))
+		Expect(reconciler.RequeueAfter).To(Equal(RetryBaseDelay))
+	})
+
+	It("should retry a failed delivery with the same notification ID", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Notifications: gateshv1alpha1.GateNotifications{Channels: []string{"webhook"}},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		channel := &gateshv1alpha1.NotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+			Spec: gateshv1alpha1.NotificationChannelSpec{
+				Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+					NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				},
+			},
+		}
+
+		responseStatus = http.StatusServiceUnavailable
+		reconciler := newReconciler(gate, configMap, channel)
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Notifications[0].Result).To(Equal(gateshv1alpha1.GateNotificationResultFailed))
+		Expect(gate.Status.Notifications[0].Message).To(ContainSubstring("unexpected response status 503"))
+		Expect(reconciler.RequeueAfter).To(Equal(RetryBaseDelay))
+
+		responseStatus = http.StatusOK
+		gate.Status.Notifications[0].LastAttemptTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(gate.Status.Notifications[0].Result).To(Equal(gateshv1alpha1.GateNotificationResultSucceeded))
+		Expect(gate.Status.Notifications[0].Attempts).To(Equal(2))
+		Expect(notificationIds).To(HaveLen(2))
+		Expect(notificationIds[0]).To(Equal(notificationIds[1]))
+	})
+
+	It("should give up after the maximum number of attempts", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Notifications: gateshv1alpha1.GateNotifications{Channels: []string{"webhook"}},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := newReconciler(gate, configMap)
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		gate.Status.Notifications[0].Attempts = NotificationMaxAttempts
+		gate.Status.Notifications[0].LastAttemptTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		reconciler.RequeueAfter = 0
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(gate.Status.Notifications[0].Attempts).To(Equal(NotificationMaxAttempts))
+		Expect(gate.Status.Notifications[0].Result).To(Equal(gateshv1alpha1.GateNotificationResultFailed))
+	})
+
+	It("should identify the notifications by the sequence number of the transition", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", UID: "uid"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+				Notifications: gateshv1alpha1.GateNotifications{Channels: []string{"webhook"}},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+		channel := &gateshv1alpha1.NotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+			Spec: gateshv1alpha1.NotificationChannelSpec{
+				Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+					NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				},
+			},
+		}
+
+		reconciler := newReconciler(gate, configMap, channel)
+		staleStatus := gate.Status.DeepCopy()
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		By("replaying the transition from a stale status with the same ID")
+		gate.Status = *staleStatus
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(notificationIds).To(Equal([]string{"uid-1"}))
+
+		By("numbering the next transition, even within the same second")
+		Expect(reconciler.Client.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(notificationIds).To(Equal([]string{"uid-1", "uid-2"}))
+	})
+})
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EvaluateTargetOnError returns the condition of a target whose objects can't be fetched, according to its onError
+// policy. The condition keeps the ErrorWhileFetching reason whatever its status.
+func (g *GateCommonReconciler) EvaluateTargetOnError(target *gateshv1alpha1.GateTarget, err error) (metav1.Condition, gateshv1alpha1.GateTargetStatus) {
+	condition := metav1.Condition{
+		Type:    target.Name,
+		Status:  metav1.ConditionFalse,
+		Reason:  TargetReasonErrorWhileFetching,
+		Message: fmt.Sprintf("not able to fetch target objects: %s", err.Error()),
+	}
+	targetStatus := gateshv1alpha1.GateTargetStatus{Name: target.Name}
+
+	switch target.OnError {
+	case gateshv1alpha1.GateTargetOnErrorTrue:
+		condition.Status = metav1.ConditionTrue
+	case gateshv1alpha1.GateTargetOnErrorUnknown:
+		condition.Status = metav1.ConditionUnknown
+	case gateshv1alpha1.GateTargetOnErrorKeepLastResult:
+		if lastCondition, lastStatus, ok := g.GetLastTargetResult(target); ok {
+			condition.Status = lastCondition.Status
+			condition.Message = fmt.Sprintf("not able to fetch target objects, last result of %s kept: %s",
+				lastStatus.LastResultTime.UTC().Format(time.RFC3339), err.Error())
+			targetStatus = lastStatus
+		}
+	}
+	g.VerboseLog("Target evaluated on error", "target", target.Name, "onError", target.OnError, "status", condition.Status)
+	return condition, targetStatus
+}
+
+// GetLastTargetResult returns the last condition and status of the target computed from its objects, if they are not
+// older than its max staleness.
+func (g *GateCommonReconciler) GetLastTargetResult(target *gateshv1alpha1.GateTarget) (metav1.Condition, gateshv1alpha1.GateTargetStatus, bool) {
+	lastCondition := meta.FindStatusCondition(g.Gate.Status.TargetConditions, target.Name)
+	if lastCondition == nil || lastCondition.Status == metav1.ConditionUnknown {
+		return metav1.Condition{}, gateshv1alpha1.GateTargetStatus{}, false
+	}
+	for _, lastStatus := range g.Gate.Status.Targets {
+		if lastStatus.Name != target.Name || lastStatus.LastResultTime == nil {
+			continue
+		}
+		if target.MaxStaleness != nil && metav1.Now().Sub(lastStatus.LastResultTime.Time) > target.MaxStaleness.Duration {
+			break
+		}
+		return *lastCondition, lastStatus, true
+	}
+	return metav1.Condition{}, gateshv1alpha1.GateTargetStatus{}, false
+}
@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("GateOnError", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var failing bool
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+		failing = false
+	})
+
+	// newClient builds a client failing to get the objects of the targets while failing is true.
+	newClient := func(objects ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if failing {
+					return errors.New("throttled")
+				}
+				return cl.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+	}
+
+	It("should evaluate the target to false by default", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := GateCommonReconciler{Context: ctx, Client: newClient(gate, configMap), Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		failing = true
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionFalse))
+		Expect(gate.Status.TargetConditions[0].Reason).To(Equal(TargetReasonErrorWhileFetching))
+	})
+
+	It("should evaluate the target to true with onError True", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						OnError: gateshv1alpha1.GateTargetOnErrorTrue,
+					},
+				},
+			},
+		}
+
+		failing = true
+		reconciler := GateCommonReconciler{Context: ctx, Client: newClient(gate), Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should keep the last result up to its max staleness with onError KeepLastResult", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						OnError: gateshv1alpha1.GateTargetOnErrorKeepLastResult,
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := GateCommonReconciler{Context: ctx, Client: newClient(gate, configMap), Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		lastResultTime := gate.Status.Targets[0].LastResultTime
+		Expect(lastResultTime).NotTo(BeNil())
+
+		By("keeping the last result")
+		failing = true
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionTrue))
+		Expect(gate.Status.TargetConditions[0].Reason).To(Equal(TargetReasonErrorWhileFetching))
+		Expect(gate.Status.Targets[0].LastResultTime).To(Equal(lastResultTime))
+		Expect(gate.Status.Targets[0].Found).To(Equal(1))
+
+		By("evaluating the target to false once the last result is stale")
+		stale := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+		gate.Status.Targets[0].LastResultTime = &stale
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should keep the state of the gate with onError Unknown", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				CloseConsolidation: gateshv1alpha1.GateCloseConsolidation{Count: 2},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						OnError: gateshv1alpha1.GateTargetOnErrorUnknown,
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := GateCommonReconciler{Context: ctx, Client: newClient(gate, configMap), Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		failing = true
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionUnknown))
+		Expect(gate.Status.ConsecutiveInvalidEvaluations).To(BeZero())
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened).Reason).To(Equal("GateConditionUnknown"))
+	})
+
+	It("should restart the soak period after an Unknown result", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Consolidation: gateshv1alpha1.GateConsolidation{Count: 1, StableFor: &metav1.Duration{Duration: time.Minute}},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						OnError: gateshv1alpha1.GateTargetOnErrorUnknown,
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		reconciler := GateCommonReconciler{Context: ctx, Client: newClient(gate, configMap), Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.ConditionTrueSince).NotTo(BeNil())
+		gate.Status.ConditionTrueSince = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+
+		failing = true
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.ConditionTrueSince).To(BeNil())
+
+		failing = false
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened).Reason).To(Equal("NotStableLongEnough"))
+	})
+})
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"fmt"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ReconcileProtection sets the protect finalizer on the objects selected by the gate, and removes it from the objects
+// being deleted once the gate is opened. While the gate itself is being deleted, all the objects are released right
+// away, whatever its state. Objects that are not selected anymore are released as well.
+func (g *GateCommonReconciler) ReconcileProtection() {
+	log := logf.FromContext(g.Context)
+
+	failed := false
+	selected := map[string]bool{}
+	protected := make([]gateshv1alpha1.GateObjectReference, 0)
+
+	for idx, protection := range g.Gate.Spec.Protect {
+		if g.Gate.Namespace != "" && protection.Selector.Namespace != "" && protection.Selector.Namespace != g.Gate.Namespace {
+			log.Error(fmt.Errorf("a Gate can only protect objects in its own namespace %s", g.Gate.Namespace), "unable to protect objects")
+			continue
+		}
+		objects, err := g.FetchGateTargetObjects(&gateshv1alpha1.GateTarget{
+			Name:     fmt.Sprintf("Protect%d", idx+1),
+			Selector: protection.Selector,
+		})
+		if err != nil {
+			log.Error(err, "unable to fetch protected objects")
+			failed = true
+			continue
+		}
+
+		for i := range objects {
+			reference := g.GetObjectReference(&objects[i])
+			selected[g.GetObjectReferenceKey(reference)] = true
+			holds, err := g.ProtectObject(&objects[i])
+			if err != nil {
+				log.Error(err, "unable to update protect finalizer", "object", g.GetObjectName(objects[i]))
+				failed = true
+			}
+			if holds {
+				protected = append(protected, reference)
+			}
+		}
+	}
+
+	for _, reference := range g.Gate.Status.ProtectedObjects {
+		if selected[g.GetObjectReferenceKey(reference)] {
+			continue
+		}
+		// Without the complete selection, an object missing from it may still be protected.
+		if failed {
+			protected = append(protected, reference)
+			continue
+		}
+		if err := g.ReleaseObject(reference); err != nil {
+			log.Error(err, "unable to release protected object", "object", g.GetObjectReferenceKey(reference))
+			failed = true
+			protected = append(protected, reference)
+		}
+	}
+
+	g.Gate.Status.ProtectedObjects = protected
+	g.ProtectionReleased = !failed && len(protected) == 0
+}
+
+// ProtectObject adds or removes the protect finalizer of the object depending on its deletion, the deletion of the
+// gate and the state of the gate. Returns whether the object holds the finalizer afterward.
+func (g *GateCommonReconciler) ProtectObject(object *unstructured.Unstructured) (bool, error) {
+	holds := controllerutil.ContainsFinalizer(object, gateshv1alpha1.GateProtectFinalizer)
+	deleting := object.GetDeletionTimestamp() != nil
+
+	// A gate being deleted can't open anymore: holding the objects being deleted would block both deletions.
+	keep := g.Gate.DeletionTimestamp.IsZero()
+	if keep && deleting {
+		keep = g.Gate.Status.State != gateshv1alpha1.GateStateOpened
+	}
+
+	// Finalizers can't be added to an object being deleted.
+	if keep && !holds && !deleting {
+		if err := g.PatchProtectFinalizer(object, true); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if !keep && holds {
+		if err := g.PatchProtectFinalizer(object, false); err != nil {
+			return true, err
+		}
+		return false, nil
+	}
+	return holds, nil
+}
+
+func (g *GateCommonReconciler) ReleaseObject(reference gateshv1alpha1.GateObjectReference) error {
+	objects, err := g.FetchGateTargetObjects(&gateshv1alpha1.GateTarget{
+		Selector: gateshv1alpha1.GateTargetSelector{
+			ApiVersion: reference.ApiVersion,
+			Kind:       reference.Kind,
+			Namespace:  reference.Namespace,
+			Name:       reference.Name,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for i := range objects {
+		if controllerutil.ContainsFinalizer(&objects[i], gateshv1alpha1.GateProtectFinalizer) {
+			if err := g.PatchProtectFinalizer(&objects[i], false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *GateCommonReconciler) PatchProtectFinalizer(object *unstructured.Unstructured, protect bool) error {
+	patch := client.MergeFromWithOptions(object.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if protect {
+		controllerutil.AddFinalizer(object, gateshv1alpha1.GateProtectFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(object, gateshv1alpha1.GateProtectFinalizer)
+	}
+	return g.Client.Patch(g.Context, object, patch)
+}
+
+func (g *GateCommonReconciler) GetObjectReference(object *unstructured.Unstructured) gateshv1alpha1.GateObjectReference {
+	return gateshv1alpha1.GateObjectReference{
+		ApiVersion: object.GetAPIVersion(),
+		Kind:       object.GetKind(),
+		Namespace:  object.GetNamespace(),
+		Name:       object.GetName(),
+	}
+}
+
+func (g *GateCommonReconciler) GetObjectReferenceKey(reference gateshv1alpha1.GateObjectReference) string {
+	return fmt.Sprintf("%s/%s/%s/%s", reference.ApiVersion, reference.Kind, reference.Namespace, reference.Name)
+}
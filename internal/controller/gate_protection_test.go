@@ -0,0 +1,307 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("GateProtection", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	getLb := func(cl client.Client) (*corev1.ConfigMap, error) {
+		result := &corev1.ConfigMap{}
+		err := cl.Get(ctx, types.NamespacedName{Name: "lb", Namespace: "default"}, result)
+		return result, err
+	}
+
+	It("should set the protect finalizer on the selected objects", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "teardown", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "App",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "app",
+						},
+					},
+				},
+				Operation: gateshv1alpha1.GateOperation{Invert: true},
+				Protect: []gateshv1alpha1.GateProtection{
+					{
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "lb",
+						},
+					},
+				},
+			},
+		}
+		app := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+		lb := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, app, lb).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		object, err := getLb(cl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controllerutil.ContainsFinalizer(object, gateshv1alpha1.GateProtectFinalizer)).To(BeTrue())
+		Expect(gate.Status.ProtectedObjects).To(ConsistOf(gateshv1alpha1.GateObjectReference{
+			ApiVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "default",
+			Name:       "lb",
+		}))
+		Expect(reconciler.ProtectionReleased).To(BeFalse())
+	})
+
+	It("should hold the deletion until the gate opens", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "teardown", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "App",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "app",
+						},
+					},
+				},
+				Operation: gateshv1alpha1.GateOperation{Invert: true},
+				Protect: []gateshv1alpha1.GateProtection{
+					{
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "lb",
+						},
+					},
+				},
+			},
+		}
+		app := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+		lb := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, app, lb).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		By("deleting the protected object while the gate is closed")
+		Expect(cl.Delete(ctx, lb)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		object, err := getLb(cl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(object.DeletionTimestamp).NotTo(BeNil())
+
+		By("deleting the app to open the gate")
+		Expect(cl.Delete(ctx, app)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		_, err = getLb(cl)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(gate.Status.ProtectedObjects).To(BeEmpty())
+		Expect(reconciler.ProtectionReleased).To(BeTrue())
+	})
+
+	It("should not release an object when the gate opens if it is not being deleted", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "teardown", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "App",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "app",
+						},
+					},
+				},
+				Operation: gateshv1alpha1.GateOperation{Invert: true},
+				Protect: []gateshv1alpha1.GateProtection{
+					{
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "lb",
+						},
+					},
+				},
+			},
+		}
+		app := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+		lb := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, app, lb).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(cl.Delete(ctx, app)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		object, err := getLb(cl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controllerutil.ContainsFinalizer(object, gateshv1alpha1.GateProtectFinalizer)).To(BeTrue())
+	})
+
+	It("should release the objects that are not being deleted when the gate is deleted", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "teardown", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "App",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "app",
+						},
+					},
+				},
+				Operation: gateshv1alpha1.GateOperation{Invert: true},
+				Protect: []gateshv1alpha1.GateProtection{
+					{
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "lb",
+						},
+					},
+				},
+			},
+		}
+		app := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+		lb := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, app, lb).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		gate.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		object, err := getLb(cl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controllerutil.ContainsFinalizer(object, gateshv1alpha1.GateProtectFinalizer)).To(BeFalse())
+		Expect(reconciler.ProtectionReleased).To(BeTrue())
+	})
+
+	It("should release the objects being deleted when the gate is deleted while closed", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "teardown", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "App",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "app",
+						},
+					},
+				},
+				Operation: gateshv1alpha1.GateOperation{Invert: true},
+				Protect: []gateshv1alpha1.GateProtection{
+					{
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "lb",
+						},
+					},
+				},
+			},
+		}
+		app := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+		lb := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, app, lb).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(cl.Delete(ctx, lb)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		gate.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		_, err := getLb(cl)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(gate.Status.ProtectedObjects).To(BeEmpty())
+		Expect(reconciler.ProtectionReleased).To(BeTrue())
+	})
+
+	It("should release the objects that are not selected anymore", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "teardown", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "App",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "app",
+						},
+					},
+				},
+				Operation: gateshv1alpha1.GateOperation{Invert: true},
+				Protect: []gateshv1alpha1.GateProtection{
+					{
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "lb",
+						},
+					},
+				},
+			},
+		}
+		app := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+		lb := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "lb", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, app, lb).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		gate.Spec.Protect = nil
+		Expect(reconciler.Reconcile()).To(Succeed())
+
+		object, err := getLb(cl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controllerutil.ContainsFinalizer(object, gateshv1alpha1.GateProtectFinalizer)).To(BeFalse())
+		Expect(gate.Status.ProtectedObjects).To(BeEmpty())
+		Expect(reconciler.ProtectionReleased).To(BeTrue())
+	})
+})
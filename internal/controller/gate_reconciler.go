@@ -4,51 +4,269 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/go-openapi/jsonpointer"
 	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"github.com/robinlioret/gate-operator/internal/audit"
+	"github.com/robinlioret/gate-operator/internal/metrics"
+	"github.com/robinlioret/gate-operator/internal/notifier"
+	"github.com/robinlioret/gate-operator/internal/tracing"
 	"github.com/robinlioret/gate-operator/internal/webhook/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type TargetConditionReason string
 
+const TargetReasonErrorWhileFetching = "ErrorWhileFetching"
+
+// TargetThresholdAll is the threshold of the targets without AtLeast validator, requiring all the found objects.
+const TargetThresholdAll = "All"
+
+// GateOutputs are the optional outputs of the reconciliations of the gates, each one disabled if nil.
+type GateOutputs struct {
+	// Notifier delivers the notifications of the gate transitions.
+	Notifier *notifier.GateNotifier
+	// CloudEvents emits the CloudEvents of the gate lifecycle.
+	CloudEvents *notifier.CloudEventEmitter
+	// Recorder records the Kubernetes events of the gates.
+	Recorder *GateEventRecorder
+	// Auditor writes the audit records of the evaluations.
+	Auditor *audit.Auditor
+	// Debug keeps the last evaluation of the gates for the debug endpoint.
+	Debug *GateDebugStore
+}
+
 type GateCommonReconciler struct {
 	Context      context.Context
 	Client       client.Client
 	Gate         *gateshv1alpha1.Gate
 	RequeueAfter time.Duration
+	GateOutputs
+
+	// TargetEvaluations holds the objects evaluated for each target by the last evaluation.
+	TargetEvaluations []TargetEvaluation
+
+	// DryRun evaluates the gate without recording metrics, for the debug endpoint.
+	DryRun bool
+
+	// VerboseTrace holds the verbose logs of the evaluation, written to the status when the gate.sh/debug annotation
+	// is "status".
+	VerboseTrace []string
+
+	// ProtectionReleased is true once no object holds the protect finalizer of the gate anymore.
+	ProtectionReleased bool
+
+	// ExportReleased is true once the state of the gate is not exported anymore.
+	ExportReleased bool
+
+	// ActionsStarted is true when a transition started actions, executed by the next reconciliation.
+	ActionsStarted bool
+
+	// NotificationsStarted is true when a transition started notifications, delivered by the next reconciliation.
+	NotificationsStarted bool
+
+	// Reconsolidating is true when the spec of an opened gate changed with the Reconsolidate policy: the gate is
+	// evaluated as a closed one.
+	Reconsolidating bool
 }
 
+type TargetEvaluation struct {
+	Target   string
+	Objects  []unstructured.Unstructured
+	Results  []bool
+	Failures [][]gateshv1alpha1.GateTargetValidatorFailure
+	Duration time.Duration
+}
+
+var RetryBaseDelay = 5 * time.Second
+var RetryMaxDelay = 5 * time.Minute
+var ActionsStartDelay = time.Second
+var NotificationsStartDelay = time.Second
+
 type TargetObjectResult struct {
 	Result  bool
 	Message string
 }
 
 func (g *GateCommonReconciler) Reconcile() error {
+	span, end := g.StartSpan("Reconcile", append(g.GetSpanAttributes(), attribute.Int64("gate.generation", g.Gate.Generation))...)
+	defer end()
+
 	log := logf.FromContext(g.Context)
 	log.Info(fmt.Sprintf("Start reconciling %s %s", g.Gate.Kind, g.Gate.Name))
+	g.ActionsStarted = false
+	g.NotificationsStarted = false
 	v1alpha1.ApplyDefaultSpec(&g.Gate.Spec)
-	result, targetConditions := g.EvaluateSpec()
-	g.UpdateGateStatusFromResult(result, targetConditions)
+	g.HandleLatchReset()
+	reevaluate := g.HandleReevaluate()
+	if g.ApplyForcedState() {
+		g.ReconcileWithoutEvaluation(0)
+		return nil
+	}
+	if g.ApplySuspend() {
+		g.ReconcileWithoutEvaluation(0)
+		return nil
+	}
+	if g.Gate.Status.LatchedAt != nil {
+		g.ReconcileWithoutEvaluation(g.GetLatchedRequeueAfter())
+		return nil
+	}
+	// The spec changes are handled by the next evaluation.
+	g.StartTimeout()
+	g.HandleSpecChange()
+	if !reevaluate && g.IsEvaluationStopped() {
+		g.ReconcileWithoutEvaluation(0)
+		return nil
+	}
+	previousState := g.Gate.Status.State
+	previousTargetConditions := g.Gate.Status.TargetConditions
+	previousConsecutiveValid := g.Gate.Status.ConsecutiveValidEvaluations
+	previousConsecutiveInvalid := g.Gate.Status.ConsecutiveInvalidEvaluations
+	g.VerboseTrace = nil
+	start := time.Now()
+	result, targetConditions, targetStatuses := g.EvaluateSpec()
+	duration := time.Since(start)
+	g.RecordReevaluate()
+	g.Gate.Status.LastEvaluatedGeneration = g.Gate.Generation
+	g.Gate.Status.ObservedGeneration = g.Gate.Generation
+	g.UpdateGateStatusFromResult(result, targetConditions, targetStatuses)
+	g.ApplyFlapping(previousState)
+	// The timeout takes precedence over the state held while flapping: a gate not opened in time fails.
+	g.ApplyTimeout()
+	g.Latch()
+	g.UpdateKstatusConditions()
+	g.UpdateStatusDebug()
+	span.SetAttributes(
+		attribute.Bool("gate.result", result == metav1.ConditionTrue),
+		attribute.String("gate.state", g.Gate.Status.State),
+		attribute.String("gate.previousState", previousState),
+		attribute.Int("gate.consecutiveValidEvaluations", g.Gate.Status.ConsecutiveValidEvaluations),
+	)
+	if previousState != g.Gate.Status.State {
+		g.HandleStateTransition(previousState)
+	}
+	g.RecordStateMetrics()
+	g.RecordEvaluationEvents(previousTargetConditions, previousConsecutiveValid, previousConsecutiveInvalid)
+	g.EmitEvaluationCloudEvents(previousState, previousTargetConditions)
+	g.WriteAuditRecord(previousState)
+	g.RecordDebugEvaluation(result == metav1.ConditionTrue, targetConditions, duration)
+	g.ExecuteActions()
+	g.DeliverNotifications()
+	g.ReconcileProtection()
+	g.ReconcileExport()
 	return nil
 }
 
+// AddGateFinalizers adds the finalizers required by the spec to a gate which is not being deleted.
+// Returns true if the gate was changed.
+func AddGateFinalizers(gate client.Object, spec *gateshv1alpha1.GateSpec) bool {
+	if !gate.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+	changed := false
+	if len(spec.Protect) > 0 {
+		changed = controllerutil.AddFinalizer(gate, gateshv1alpha1.GateProtectFinalizer) || changed
+	}
+	if spec.Export.ConfigMap != nil {
+		changed = controllerutil.AddFinalizer(gate, gateshv1alpha1.GateExportFinalizer) || changed
+	}
+	return changed
+}
+
+// RemoveGateFinalizers removes the finalizers of the gate once their cleanup is done, if the gate is being deleted or
+// the spec doesn't require them anymore. Returns true if the gate was changed.
+func (g *GateCommonReconciler) RemoveGateFinalizers(gate client.Object) bool {
+	deleting := !gate.GetDeletionTimestamp().IsZero()
+	changed := false
+	if (deleting || len(g.Gate.Spec.Protect) == 0) && g.ProtectionReleased {
+		changed = controllerutil.RemoveFinalizer(gate, gateshv1alpha1.GateProtectFinalizer) || changed
+	}
+	if (deleting || g.Gate.Spec.Export.ConfigMap == nil) && g.ExportReleased {
+		changed = controllerutil.RemoveFinalizer(gate, gateshv1alpha1.GateExportFinalizer) || changed
+	}
+	return changed
+}
+
+func (g *GateCommonReconciler) HandleStateTransition(previousState gateshv1alpha1.GateState) {
+	log := logf.FromContext(g.Context)
+	log.Info(fmt.Sprintf("%s %s changed state", g.Gate.Kind, g.Gate.Name), "from", previousState, "to", g.Gate.Status.State)
+	g.RecordTransitionEvent(previousState)
+	g.RecordHistory(previousState)
+	if previousState != "" {
+		metrics.RecordTransition(g.GetMetricsLabels(), g.Gate.Status.State, g.GetLastTransitionTime().Time)
+	}
+
+	// A gate being created closed is not a transition.
+	if previousState != "" || g.Gate.Status.State == gateshv1alpha1.GateStateOpened {
+		g.StartActions(g.Gate.Status.State)
+		g.StartNotifications(g.Gate.Status.State, previousState)
+		g.EmitTransitionCloudEvent(previousState)
+	}
+}
+
+func (g *GateCommonReconciler) GetRetryDelay(attempts int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempts && delay < RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, RetryMaxDelay)
+}
+
+func (g *GateCommonReconciler) ShortenRequeueAfter(duration time.Duration) {
+	if g.RequeueAfter == 0 || duration < g.RequeueAfter {
+		g.RequeueAfter = duration
+	}
+}
+
+// GetGateKind returns the kind of the reconciled gate. The kind of typed objects read from the API is not set.
+func (g *GateCommonReconciler) GetGateKind() string {
+	if g.Gate.Kind == "" {
+		return "Gate"
+	}
+	return g.Gate.Kind
+}
+
+// GetChangedTargetConditions returns the target conditions whose status changed since the previous evaluation.
+// Targets which were not evaluated before are ignored.
+func (g *GateCommonReconciler) GetChangedTargetConditions(previousTargetConditions []metav1.Condition) []*metav1.Condition {
+	changed := make([]*metav1.Condition, 0)
+	for idx := range g.Gate.Status.TargetConditions {
+		condition := &g.Gate.Status.TargetConditions[idx]
+		previousCondition := meta.FindStatusCondition(previousTargetConditions, condition.Type)
+		if previousCondition != nil && previousCondition.Status != condition.Status {
+			changed = append(changed, condition)
+		}
+	}
+	return changed
+}
+
+func (g *GateCommonReconciler) GetFailingTargets() []string {
+	failing := make([]string, 0)
+	for _, condition := range g.Gate.Status.TargetConditions {
+		if condition.Status != metav1.ConditionTrue {
+			failing = append(failing, condition.Type)
+		}
+	}
+	return failing
+}
+
 func (g *GateCommonReconciler) GetObjectName(object unstructured.Unstructured) string {
 	return fmt.Sprintf("%s/%s", object.GetNamespace(), object.GetName())
 }
 
 func (g *GateCommonReconciler) UpdateGateStatusFromResult(
-	result bool,
+	result metav1.ConditionStatus,
 	targetConditions []metav1.Condition,
+	targetStatuses []gateshv1alpha1.GateTargetStatus,
 ) {
 	var message string
 	var reason string
@@ -56,13 +274,26 @@ func (g *GateCommonReconciler) UpdateGateStatusFromResult(
 	var closedCondition metav1.ConditionStatus
 	var requeAfter time.Duration
 	var state gateshv1alpha1.GateState
-
-	if result {
+	previousConsecutiveValidEvaluations := g.Gate.Status.ConsecutiveValidEvaluations
+	previousConsecutiveInvalidEvaluations := g.Gate.Status.ConsecutiveInvalidEvaluations
+	now := metav1.Now()
+	opened := g.Gate.Status.State == gateshv1alpha1.GateStateOpened && !g.Reconsolidating
+
+	switch result {
+	case metav1.ConditionTrue:
+		g.Gate.Status.ConsecutiveInvalidEvaluations = 0
 		if g.Gate.Status.ConsecutiveValidEvaluations < g.Gate.Spec.Consolidation.Count {
 			g.Gate.Status.ConsecutiveValidEvaluations += 1
 		}
+		if g.Gate.Status.ConditionTrueSince == nil {
+			// Truncated as serialized, so that the soak period elapses at the same time after a restart.
+			since := metav1.NewTime(now.Truncate(time.Second))
+			g.Gate.Status.ConditionTrueSince = &since
+		}
+		soakRemaining := g.GetSoakRemaining(now.Time)
 
-		if g.Gate.Status.ConsecutiveValidEvaluations >= g.Gate.Spec.Consolidation.Count {
+		if g.Gate.Status.ConsecutiveValidEvaluations >= g.Gate.Spec.Consolidation.Count &&
+			(soakRemaining <= 0 || opened) {
 			g.Gate.Status.ConsecutiveValidEvaluations = g.Gate.Spec.Consolidation.Count
 			requeAfter = g.Gate.Spec.EvaluationPeriod.Duration
 			state = gateshv1alpha1.GateStateOpened
@@ -77,48 +308,127 @@ func (g *GateCommonReconciler) UpdateGateStatusFromResult(
 			reason = "NotEnoughConsecutiveValidEvaluations"
 			openedCondition = metav1.ConditionFalse
 			closedCondition = metav1.ConditionTrue
+			if g.Gate.Status.ConsecutiveValidEvaluations >= g.Gate.Spec.Consolidation.Count {
+				// Reconciled again exactly when the soak period elapses.
+				requeAfter = soakRemaining
+				message = fmt.Sprintf("requires the condition to be true for %s to open, true since %s",
+					g.Gate.Spec.Consolidation.StableFor.Duration, g.Gate.Status.ConditionTrueSince.UTC().Format(time.RFC3339))
+				reason = "NotStableLongEnough"
+			}
 		}
-	} else {
-		g.Gate.Status.ConsecutiveValidEvaluations = 0
+	case metav1.ConditionUnknown:
+		// The consolidation doesn't progress while the result is unknown: the gate keeps its state. The condition can't
+		// be proven true meanwhile, so the soak period restarts once it's known again.
+		g.Gate.Status.ConditionTrueSince = nil
 		requeAfter = g.Gate.Spec.Consolidation.Delay.Duration
 		state = gateshv1alpha1.GateStateClosed
-		message = "Gate was evaluated to false"
-		reason = "GateConditionNotMet"
 		openedCondition = metav1.ConditionFalse
 		closedCondition = metav1.ConditionTrue
+		if opened {
+			state = gateshv1alpha1.GateStateOpened
+			openedCondition = metav1.ConditionTrue
+			closedCondition = metav1.ConditionFalse
+		}
+		message = fmt.Sprintf("Gate was evaluated to unknown, state kept to %s", state)
+		reason = "GateConditionUnknown"
+	default:
+		g.Gate.Status.ConditionTrueSince = nil
+		if g.Gate.Status.ConsecutiveInvalidEvaluations < g.Gate.Spec.CloseConsolidation.Count {
+			g.Gate.Status.ConsecutiveInvalidEvaluations += 1
+		}
+
+		if opened && g.Gate.Status.ConsecutiveInvalidEvaluations < g.Gate.Spec.CloseConsolidation.Count {
+			// An opened gate only closes after sustained failures.
+			requeAfter = g.Gate.Spec.CloseConsolidation.Delay.Duration
+			state = gateshv1alpha1.GateStateOpened
+			message = fmt.Sprintf("requires %d/%d consecutive invalid evaluations to close", g.Gate.Status.ConsecutiveInvalidEvaluations, g.Gate.Spec.CloseConsolidation.Count)
+			reason = "NotEnoughConsecutiveInvalidEvaluations"
+			openedCondition = metav1.ConditionTrue
+			closedCondition = metav1.ConditionFalse
+		} else {
+			g.Gate.Status.ConsecutiveValidEvaluations = 0
+			requeAfter = g.Gate.Spec.Consolidation.Delay.Duration
+			state = gateshv1alpha1.GateStateClosed
+			message = "Gate was evaluated to false"
+			reason = "GateConditionNotMet"
+			openedCondition = metav1.ConditionFalse
+			closedCondition = metav1.ConditionTrue
+		}
 	}
 
+	g.VerboseLog("Consolidation computed",
+		"result", result,
+		"previousConsecutiveValidEvaluations", previousConsecutiveValidEvaluations,
+		"consecutiveValidEvaluations", g.Gate.Status.ConsecutiveValidEvaluations,
+		"consolidationCount", g.Gate.Spec.Consolidation.Count,
+		"previousConsecutiveInvalidEvaluations", previousConsecutiveInvalidEvaluations,
+		"consecutiveInvalidEvaluations", g.Gate.Status.ConsecutiveInvalidEvaluations,
+		"closeConsolidationCount", g.Gate.Spec.CloseConsolidation.Count,
+		"conditionTrueSince", g.Gate.Status.ConditionTrueSince,
+		"state", state,
+		"requeueAfter", requeAfter.String(),
+	)
+
 	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateStateOpened, Status: openedCondition, Reason: reason, Message: message})
 	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateStateClosed, Status: closedCondition, Reason: reason, Message: message})
 	g.Gate.Status.TargetConditions = targetConditions
+	g.Gate.Status.Targets = targetStatuses
 	g.RequeueAfter = requeAfter
 	g.Gate.Status.State = state
+	g.Gate.Status.LastEvaluationTime = &now
 }
 
-func (g *GateCommonReconciler) EvaluateSpec() (bool, []metav1.Condition) {
+func (g *GateCommonReconciler) GetSoakRemaining(now time.Time) time.Duration {
+	stableFor := g.Gate.Spec.Consolidation.StableFor
+	if stableFor == nil || g.Gate.Status.ConditionTrueSince == nil {
+		return 0
+	}
+	return g.Gate.Status.ConditionTrueSince.Add(stableFor.Duration).Sub(now)
+}
+
+func (g *GateCommonReconciler) EvaluateSpec() (metav1.ConditionStatus, []metav1.Condition, []gateshv1alpha1.GateTargetStatus) {
 	targetConditions := make([]metav1.Condition, 0)
+	targetStatuses := make([]gateshv1alpha1.GateTargetStatus, 0)
+	g.TargetEvaluations = make([]TargetEvaluation, 0, len(g.Gate.Spec.Targets))
 	for _, target := range g.Gate.Spec.Targets {
-		meta.SetStatusCondition(&targetConditions, g.EvaluateTarget(&target))
+		condition, targetStatus := g.EvaluateTarget(&target)
+		meta.SetStatusCondition(&targetConditions, condition)
+		targetStatuses = append(targetStatuses, targetStatus)
 	}
 	result := g.ComputeOperation(targetConditions)
-	return result, targetConditions
+	g.VerboseLog("Operation computed", "operator", g.Gate.Spec.Operation.Operator, "invert", g.Gate.Spec.Operation.Invert, "result", result)
+	return result, targetConditions, targetStatuses
 }
 
-func (g *GateCommonReconciler) EvaluateTarget(target *gateshv1alpha1.GateTarget) metav1.Condition {
+func (g *GateCommonReconciler) EvaluateTarget(target *gateshv1alpha1.GateTarget) (metav1.Condition, gateshv1alpha1.GateTargetStatus) {
+	span, end := g.StartSpan("EvaluateTarget", append(g.GetSpanAttributes(),
+		attribute.String("gate.target", target.Name),
+		attribute.String("k8s.apiVersion", target.Selector.ApiVersion),
+		attribute.String("k8s.kind", target.Selector.Kind),
+	)...)
+	defer end()
+
 	log := logf.FromContext(g.Context)
 
+	targetStatus := gateshv1alpha1.GateTargetStatus{Name: target.Name}
+	start := time.Now()
 	objects, err := g.FetchGateTargetObjects(target)
 	if err != nil {
 		log.Error(err, "unable to fetch target objects")
-		return metav1.Condition{Type: target.Name, Status: metav1.ConditionFalse, Reason: "ErrorWhileFetching", Message: fmt.Sprintf("not able to fetch target objects: %s", err.Error())}
+		g.RecordFetchErrorEvent(target, err)
+		if !g.DryRun {
+			metrics.RecordFetchError(g.GetMetricsLabels(), target.Name, GetFetchErrorReason(err))
+		}
+		tracing.RecordError(span, err)
+		return g.EvaluateTargetOnError(target, err)
 	}
 
-	var message []string
 	atLeast := -1
 	results := make([]bool, len(objects))
 	for i := range results {
 		results[i] = true
 	}
+	failures := make([][]gateshv1alpha1.GateTargetValidatorFailure, len(objects))
 
 	for _, validator := range target.Validators {
 		atLeastCount := atLeast
@@ -129,124 +439,219 @@ func (g *GateCommonReconciler) EvaluateTarget(target *gateshv1alpha1.GateTarget)
 		if validator.AtLeast.Percent > 0 {
 			atLeastPercent = validator.AtLeast.Percent * len(objects) / 100
 		}
+		if atLeastCount > atLeast && atLeastCount >= atLeastPercent {
+			targetStatus.Threshold = fmt.Sprintf("AtLeast %d", validator.AtLeast.Count)
+		} else if atLeastPercent > atLeast {
+			targetStatus.Threshold = fmt.Sprintf("AtLeast %d%%", validator.AtLeast.Percent)
+		}
 		atLeast = max(atLeastCount, atLeastPercent, atLeast)
 
 		if validator.MatchCondition.Type != "" {
-			message = g.EvaluateTargetMatchCondition(objects, results, message, validator)
+			g.EvaluateTargetMatchCondition(objects, results, failures, validator)
 		}
 		if validator.JsonPointer.Pointer != "" {
-			message = g.EvaluateTargetJsonPointer(objects, results, message, validator)
+			g.EvaluateTargetJsonPointer(objects, results, failures, validator)
 		}
 	}
 
-	result, message := g.ComputeTargetEvaluationResult(atLeast, results, message)
+	if atLeast <= 0 {
+		targetStatus.Threshold = TargetThresholdAll
+	}
+	result, required := g.ComputeTargetEvaluationResult(atLeast, results)
+	targetStatus.Found = len(objects)
+	targetStatus.Matched = CountValidObjects(results)
+	targetStatus.Required = required
+	targetStatus.FailingObjects = GetFailingObjects(objects, failures)
+
 	status := metav1.ConditionFalse
 	reason := "ConditionNotMet"
 	if result {
 		status = metav1.ConditionTrue
 		reason = "ConditionMet"
 	}
-	return metav1.Condition{Type: target.Name, Status: status, Reason: reason, Message: strings.Join(message, "\n")}
+	evaluationTime := metav1.Now()
+	targetStatus.LastResultTime = &evaluationTime
+	duration := time.Since(start)
+	g.TargetEvaluations = append(g.TargetEvaluations, TargetEvaluation{Target: target.Name, Objects: objects, Results: results, Failures: failures, Duration: duration})
+	if !g.DryRun {
+		metrics.RecordTargetEvaluation(g.GetMetricsLabels(), target.Name, duration, len(objects))
+	}
+	span.SetAttributes(
+		attribute.Int("gate.objects.count", len(objects)),
+		attribute.Int("gate.objects.valid", targetStatus.Matched),
+		attribute.String("gate.target.status", string(status)),
+		attribute.String("gate.target.reason", reason),
+	)
+	message := fmt.Sprintf("%d/%d valid objects, %d objects found", targetStatus.Matched, required, len(objects))
+	return metav1.Condition{Type: target.Name, Status: status, Reason: reason, Message: message}, targetStatus
+}
+
+func GetFailingObjects(objects []unstructured.Unstructured, failures [][]gateshv1alpha1.GateTargetValidatorFailure) []gateshv1alpha1.GateTargetFailingObject {
+	var failingObjects []gateshv1alpha1.GateTargetFailingObject
+	for idx, object := range objects {
+		if len(failures[idx]) == 0 {
+			continue
+		}
+		if len(failingObjects) == gateshv1alpha1.GateTargetFailingObjectsMaxEntries {
+			break
+		}
+		failingObjects = append(failingObjects, gateshv1alpha1.GateTargetFailingObject{
+			Namespace: object.GetNamespace(),
+			Name:      object.GetName(),
+			Failures:  failures[idx],
+		})
+	}
+	return failingObjects
 }
 
-func (g *GateCommonReconciler) EvaluateTargetMatchCondition(objects []unstructured.Unstructured, results []bool, message []string, validator gateshv1alpha1.GateTargetValidator) []string {
+func (g *GateCommonReconciler) EvaluateTargetMatchCondition(objects []unstructured.Unstructured, results []bool, failures [][]gateshv1alpha1.GateTargetValidatorFailure, validator gateshv1alpha1.GateTargetValidator) {
+	span, end := g.StartSpan("EvaluateTargetMatchCondition",
+		attribute.String("validator.type", gateshv1alpha1.GateValidatorMatchCondition),
+		attribute.String("validator.condition.type", validator.MatchCondition.Type),
+		attribute.String("validator.condition.status", string(validator.MatchCondition.Status)),
+		attribute.Int("gate.objects.count", len(objects)),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("gate.objects.valid", CountValidObjects(results)))
+		end()
+	}()
+
+	g.VerboseLog("Evaluating validator", "validator", gateshv1alpha1.GateValidatorMatchCondition,
+		"conditionType", validator.MatchCondition.Type, "conditionStatus", validator.MatchCondition.Status)
+	fail := func(idx int, message string) {
+		results[idx] = false
+		failures[idx] = append(failures[idx], gateshv1alpha1.GateTargetValidatorFailure{Validator: gateshv1alpha1.GateValidatorMatchCondition, Message: message})
+		g.VerboseLog("Object rejected by the validator", "validator", gateshv1alpha1.GateValidatorMatchCondition,
+			"object", g.GetObjectName(objects[idx]), "message", message)
+	}
 	for idx, object := range objects {
 		objectConditions, err := g.GetObjectStatusConditions(&object)
 		if err != nil {
-			results[idx] = false
-			message = append(message, fmt.Sprintf("[%s] error while fetching condition %s: %s", g.GetObjectName(object), validator.MatchCondition.Type, err.Error()))
+			fail(idx, fmt.Sprintf("error while fetching condition %s: %s", validator.MatchCondition.Type, err.Error()))
 			continue
 		}
 		if len(objectConditions) == 0 {
-			results[idx] = false
-			message = append(message, fmt.Sprintf("[%s] object doesn't have conditions", g.GetObjectName(object)))
+			fail(idx, "object doesn't have conditions")
 			continue
 		}
 
 		condition := meta.FindStatusCondition(objectConditions, validator.MatchCondition.Type)
 		if condition == nil {
-			results[idx] = false
-			message = append(message, fmt.Sprintf("[%s] condition %s is missing", g.GetObjectName(object), validator.MatchCondition.Type))
+			fail(idx, fmt.Sprintf("condition %s is missing", validator.MatchCondition.Type))
 			continue
 		}
 		if condition.Status != validator.MatchCondition.Status {
-			results[idx] = false
-			message = append(message, fmt.Sprintf("[%s] condition %s is wrong (expected %s, got %s)", g.GetObjectName(object), validator.MatchCondition.Type, string(validator.MatchCondition.Status), string(condition.Status)))
+			fail(idx, fmt.Sprintf("condition %s is wrong (expected %s, got %s)", validator.MatchCondition.Type, string(validator.MatchCondition.Status), string(condition.Status)))
 			continue
 		}
+		g.VerboseLog("Object accepted by the validator", "validator", gateshv1alpha1.GateValidatorMatchCondition,
+			"object", g.GetObjectName(object), "conditionStatus", condition.Status)
 	}
-	return message
 }
 
-func (g *GateCommonReconciler) EvaluateTargetJsonPointer(objects []unstructured.Unstructured, results []bool, message []string, validator gateshv1alpha1.GateTargetValidator) []string {
+func (g *GateCommonReconciler) EvaluateTargetJsonPointer(objects []unstructured.Unstructured, results []bool, failures [][]gateshv1alpha1.GateTargetValidatorFailure, validator gateshv1alpha1.GateTargetValidator) {
+	span, end := g.StartSpan("EvaluateTargetJsonPointer",
+		attribute.String("validator.type", gateshv1alpha1.GateValidatorJsonPointer),
+		attribute.String("validator.jsonPointer.pointer", validator.JsonPointer.Pointer),
+		attribute.String("validator.jsonPointer.value", validator.JsonPointer.Value),
+		attribute.Int("gate.objects.count", len(objects)),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("gate.objects.valid", CountValidObjects(results)))
+		end()
+	}()
+
+	g.VerboseLog("Evaluating validator", "validator", gateshv1alpha1.GateValidatorJsonPointer,
+		"pointer", validator.JsonPointer.Pointer, "value", validator.JsonPointer.Value)
+	fail := func(idx int, message string) {
+		results[idx] = false
+		failures[idx] = append(failures[idx], gateshv1alpha1.GateTargetValidatorFailure{Validator: gateshv1alpha1.GateValidatorJsonPointer, Message: message})
+		g.VerboseLog("Object rejected by the validator", "validator", gateshv1alpha1.GateValidatorJsonPointer,
+			"object", g.GetObjectName(objects[idx]), "message", message)
+	}
 	for idx, object := range objects {
 		fieldValue, err := g.GetObjectFieldByJsonPointer(&object, validator.JsonPointer.Pointer)
 		if err != nil {
-			results[idx] = false
-			message = append(message, fmt.Sprintf("[%s] error while fetching field value for the JSON Pointer %s: %s", g.GetObjectName(object), validator.JsonPointer.Pointer, err.Error()))
+			fail(idx, fmt.Sprintf("error while fetching field value for the JSON Pointer %s: %s", validator.JsonPointer.Pointer, err.Error()))
 			continue
 		}
 
 		if fieldValue != validator.JsonPointer.Value {
-			results[idx] = false
-			message = append(message, fmt.Sprintf("[%s] field value not matching expected for the JSON Pointer %s '%s', got '%s'", g.GetObjectName(object), validator.JsonPointer.Pointer, validator.JsonPointer.Value, fieldValue))
+			fail(idx, fmt.Sprintf("field value not matching expected for the JSON Pointer %s '%s', got '%s'", validator.JsonPointer.Pointer, validator.JsonPointer.Value, fieldValue))
 			continue
 		}
+		g.VerboseLog("Object accepted by the validator", "validator", gateshv1alpha1.GateValidatorJsonPointer,
+			"object", g.GetObjectName(object), "value", fieldValue)
 	}
-	return message
 }
 
-func (g *GateCommonReconciler) ComputeTargetEvaluationResult(atLeast int, results []bool, message []string) (bool, []string) {
+func (g *GateCommonReconciler) ComputeTargetEvaluationResult(atLeast int, results []bool) (bool, int) {
+	span, end := g.StartSpan("ComputeTargetEvaluationResult", attribute.String("validator.type", "AtLeast"))
+	defer end()
+
 	objectsCount := len(results)
 	if atLeast <= 0 {
 		// If not specified, need at least one object or all the found objects to match.
 		atLeast = max(1, objectsCount)
 	}
-	count := 0
-	for _, result := range results {
-		if result {
-			count++
-		}
-	}
-
-	message = append(message,
-		fmt.Sprintf("%d objects found", objectsCount),
-		fmt.Sprintf("%d objects match target validators", count),
-		fmt.Sprintf("%d/%d valid objects", count, atLeast),
+	count := CountValidObjects(results)
+	span.SetAttributes(
+		attribute.Int("validator.atLeast", atLeast),
+		attribute.Int("gate.objects.count", objectsCount),
+		attribute.Int("gate.objects.valid", count),
+		attribute.Bool("validator.result", count >= atLeast),
 	)
-	return count >= atLeast, message
+	g.VerboseLog("Target threshold computed", "objects", objectsCount, "valid", count, "required", atLeast, "result", count >= atLeast)
+	return count >= atLeast, atLeast
 }
 
-func (g *GateCommonReconciler) ComputeOperation(targetConditions []metav1.Condition) bool {
-	var result bool
-	switch g.Gate.Spec.Operation.Operator {
-	case gateshv1alpha1.GateOperatorOr:
-		result = false
-		for _, targetCondition := range targetConditions {
-			if targetCondition.Status == metav1.ConditionTrue {
-				result = true
-				break
-			}
+// ComputeOperation combines the target conditions with a three-valued logic: an unknown target makes the result
+// unknown, unless the other targets are enough to decide it (a false target with And, a true one with Or).
+func (g *GateCommonReconciler) ComputeOperation(targetConditions []metav1.Condition) metav1.ConditionStatus {
+	decisive, other := metav1.ConditionFalse, metav1.ConditionTrue
+	if g.Gate.Spec.Operation.Operator == gateshv1alpha1.GateOperatorOr {
+		decisive, other = other, decisive
+	}
+	result := other
+	for _, targetCondition := range targetConditions {
+		switch targetCondition.Status {
+		case decisive:
+			result = decisive
+		case metav1.ConditionUnknown:
+			result = metav1.ConditionUnknown
 		}
-
-	default: // And
-		result = true
-		for _, targetCondition := range targetConditions {
-			if targetCondition.Status != metav1.ConditionTrue {
-				result = false
-				break
-			}
+		if result == decisive {
+			break
 		}
 	}
 	if g.Gate.Spec.Operation.Invert {
-		result = !result
+		switch result {
+		case metav1.ConditionTrue:
+			result = metav1.ConditionFalse
+		case metav1.ConditionFalse:
+			result = metav1.ConditionTrue
+		}
 	}
 	return result
 }
 
 // FetchGateTargetObjects retrieves Kubernetes objects based on the GateTarget specification.
 // It handles both Name-based and LabelSelector-based lookups and returns a slice of unstructured objects.
-func (g *GateCommonReconciler) FetchGateTargetObjects(gateTarget *gateshv1alpha1.GateTarget) ([]unstructured.Unstructured, error) {
+func (g *GateCommonReconciler) FetchGateTargetObjects(gateTarget *gateshv1alpha1.GateTarget) (objects []unstructured.Unstructured, err error) {
+	span, end := g.StartSpan("FetchGateTargetObjects",
+		attribute.String("gate.target", gateTarget.Name),
+		attribute.String("k8s.apiVersion", gateTarget.Selector.ApiVersion),
+		attribute.String("k8s.kind", gateTarget.Selector.Kind),
+		attribute.String("k8s.namespace", gateTarget.Selector.Namespace),
+		attribute.String("k8s.name", gateTarget.Selector.Name),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Int("gate.objects.count", len(objects)))
+		tracing.RecordError(span, err)
+		g.VerboseLogFetchedObjects(gateTarget, objects, err)
+		end()
+	}()
+
 	// Determine the namespace to use
 	namespace := gateTarget.Selector.Namespace
 	if namespace == "" {
@@ -276,8 +681,6 @@ func (g *GateCommonReconciler) FetchGateTargetObjects(gateTarget *gateshv1alpha1
 		return nil, fmt.Errorf("either name or labelSelector must be specified in GateTarget %s", gateTarget.Selector.Name)
 	}
 
-	var objects []unstructured.Unstructured
-
 	if gateTarget.Selector.Name != "" {
 		// Case 1: Fetch by Name
 		obj := &unstructured.Unstructured{}
@@ -2,11 +2,13 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -154,7 +156,17 @@ var _ = Describe("GateCommonReconciler", func() {
 			Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateClosed).Status).To(Equal(metav1.ConditionTrue))
 			Expect(gate.Status.TargetConditions).To(HaveLen(1))
 			Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionFalse))
-			Expect(gate.Status.TargetConditions[0].Message).To(ContainSubstring("condition Ready is wrong (expected True, got False)"))
+			Expect(gate.Status.TargetConditions[0].Message).To(Equal("0/1 valid objects, 1 objects found"))
+			Expect(gate.Status.Targets).To(HaveLen(1))
+			Expect(gate.Status.Targets[0].FailingObjects).To(Equal([]gateshv1alpha1.GateTargetFailingObject{
+				{
+					Namespace: "default",
+					Name:      "target-pod",
+					Failures: []gateshv1alpha1.GateTargetValidatorFailure{
+						{Validator: gateshv1alpha1.GateValidatorMatchCondition, Message: "condition Ready is wrong (expected True, got False)"},
+					},
+				},
+			}))
 		})
 
 		It("should open the gate for a single target by name with matching jsonpointer", func() {
@@ -360,7 +372,12 @@ var _ = Describe("GateCommonReconciler", func() {
 			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
 			Expect(gate.Status.TargetConditions).To(HaveLen(1))
 			Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionFalse))
-			Expect(gate.Status.TargetConditions[0].Message).To(ContainSubstring("0 objects found\n0 objects match target validators\n0/1 valid objects"))
+			Expect(gate.Status.TargetConditions[0].Message).To(Equal("0/1 valid objects, 0 objects found"))
+			Expect(gate.Status.Targets).To(HaveLen(1))
+			Expect(gate.Status.Targets[0].LastResultTime).NotTo(BeNil())
+			Expect(gate.Status.Targets).To(Equal([]gateshv1alpha1.GateTargetStatus{
+				{Name: gate.Spec.Targets[0].Name, Found: 0, Matched: 0, Required: 1, Threshold: TargetThresholdAll, LastResultTime: gate.Status.Targets[0].LastResultTime},
+			}))
 		})
 
 		It("should close the gate for multiple targets with AND operation where one fails", func() {
@@ -714,7 +731,8 @@ var _ = Describe("GateCommonReconciler", func() {
 
 			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
 			Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionTrue))
-			Expect(gate.Status.TargetConditions[0].Message).To(ContainSubstring("2 objects found\n2 objects match target validators\n2/2 valid objects"))
+			Expect(gate.Status.TargetConditions[0].Message).To(Equal("2/2 valid objects, 2 objects found"))
+			Expect(gate.Status.Targets[0].FailingObjects).To(BeEmpty())
 		})
 
 		It("should close the gate if not all objects match validators in label selector", func() {
@@ -803,7 +821,12 @@ var _ = Describe("GateCommonReconciler", func() {
 
 			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
 			Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionFalse))
-			Expect(gate.Status.TargetConditions[0].Message).To(ContainSubstring("2 objects found\n1 objects match target validators\n1/2 valid objects"))
+			Expect(gate.Status.TargetConditions[0].Message).To(Equal("1/2 valid objects, 2 objects found"))
+			Expect(gate.Status.Targets[0].Found).To(Equal(2))
+			Expect(gate.Status.Targets[0].Matched).To(Equal(1))
+			Expect(gate.Status.Targets[0].Required).To(Equal(2))
+			Expect(gate.Status.Targets[0].FailingObjects).To(HaveLen(1))
+			Expect(gate.Status.Targets[0].FailingObjects[0].Name).To(Equal("pod2"))
 		})
 
 		It("should set error condition if invalid label selector", func() {
@@ -946,6 +969,7 @@ var _ = Describe("GateCommonReconciler", func() {
 			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
 			Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionTrue))
 			Expect(gate.Status.TargetConditions[0].Message).To(ContainSubstring("1/1 valid objects")) // atLeast=1 in message, but result ignores it
+			Expect(gate.Status.Targets[0].Threshold).To(Equal("AtLeast 1"))
 		})
 
 		It("should handle target with atLeast with an percent count requirements", func() {
@@ -1036,6 +1060,8 @@ var _ = Describe("GateCommonReconciler", func() {
 			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
 			Expect(gate.Status.TargetConditions[0].Status).To(Equal(metav1.ConditionTrue))
 			Expect(gate.Status.TargetConditions[0].Message).To(ContainSubstring("1/1 valid objects")) // atLeast=1 in message, but result ignores it
+			Expect(gate.Status.Targets[0].Threshold).To(Equal("AtLeast 50%"))
+			Expect(gate.Status.Targets[0].Required).To(Equal(1))
 		})
 
 		It("should keep the gate closed even if conditions are met but need multiple consecutive checks", func() {
@@ -1115,6 +1141,117 @@ var _ = Describe("GateCommonReconciler", func() {
 				Expect(reconciler.RequeueAfter).To(Equal(5 * time.Minute))
 			}
 		})
+
+		It("should keep the gate opened until enough consecutive invalid checks", func() {
+			By("creating an opened gate and a ConfigMap")
+			gate := &gateshv1alpha1.Gate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+				Spec: gateshv1alpha1.GateSpec{
+					EvaluationPeriod:   &metav1.Duration{Duration: 5 * time.Minute},
+					CloseConsolidation: gateshv1alpha1.GateCloseConsolidation{Count: 3, Delay: &metav1.Duration{Duration: time.Minute}},
+					Targets: []gateshv1alpha1.GateTarget{
+						{
+							Name: "Config",
+							Selector: gateshv1alpha1.GateTargetSelector{
+								ApiVersion: "v1",
+								Kind:       "ConfigMap",
+								Name:       "config",
+							},
+						},
+					},
+				},
+			}
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+			reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+
+			By("keeping the gate opened on a single failure")
+			Expect(cl.Delete(ctx, configMap)).To(Succeed())
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+			Expect(gate.Status.ConsecutiveInvalidEvaluations).To(Equal(1))
+			opened := meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened)
+			Expect(opened.Status).To(Equal(metav1.ConditionTrue))
+			Expect(opened.Reason).To(Equal("NotEnoughConsecutiveInvalidEvaluations"))
+			Expect(reconciler.RequeueAfter).To(Equal(time.Minute))
+
+			By("resetting the count on a valid check")
+			configMap.ResourceVersion = ""
+			Expect(cl.Create(ctx, configMap)).To(Succeed())
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+			Expect(gate.Status.ConsecutiveInvalidEvaluations).To(Equal(0))
+			Expect(reconciler.RequeueAfter).To(Equal(5 * time.Minute))
+
+			By("closing the gate after sustained failures")
+			Expect(cl.Delete(ctx, configMap)).To(Succeed())
+			for range 2 {
+				Expect(reconciler.Reconcile()).To(Succeed())
+				Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+			}
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+			Expect(gate.Status.ConsecutiveInvalidEvaluations).To(Equal(3))
+			Expect(gate.Status.ConsecutiveValidEvaluations).To(Equal(0))
+
+			By("not abusively incrementing the count once closed")
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.ConsecutiveInvalidEvaluations).To(Equal(3))
+		})
+
+		It("should open the gate once the condition is true for the soak period", func() {
+			By("creating a gate and a ConfigMap")
+			gate := &gateshv1alpha1.Gate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+				Spec: gateshv1alpha1.GateSpec{
+					EvaluationPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+					Consolidation:    gateshv1alpha1.GateConsolidation{StableFor: &metav1.Duration{Duration: 10 * time.Minute}},
+					Targets: []gateshv1alpha1.GateTarget{
+						{
+							Name: "Config",
+							Selector: gateshv1alpha1.GateTargetSelector{
+								ApiVersion: "v1",
+								Kind:       "ConfigMap",
+								Name:       "config",
+							},
+						},
+					},
+				},
+			}
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+			reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+			By("keeping the gate closed during the soak period")
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+			Expect(gate.Status.ConditionTrueSince).NotTo(BeNil())
+			Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened).Reason).To(Equal("NotStableLongEnough"))
+			Expect(reconciler.RequeueAfter).To(BeNumerically("~", 10*time.Minute, time.Second))
+
+			By("requeuing when the soak period elapses")
+			since := metav1.NewTime(gate.Status.ConditionTrueSince.Add(-8 * time.Minute))
+			gate.Status.ConditionTrueSince = &since
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+			Expect(gate.Status.ConditionTrueSince.Time).To(Equal(since.Time))
+			Expect(reconciler.RequeueAfter).To(BeNumerically("~", 2*time.Minute, time.Second))
+
+			By("opening the gate once the soak period elapsed")
+			since = metav1.NewTime(since.Add(-2 * time.Minute))
+			gate.Status.ConditionTrueSince = &since
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+			Expect(reconciler.RequeueAfter).To(Equal(5 * time.Minute))
+
+			By("resetting the soak period once the condition is false")
+			Expect(cl.Delete(ctx, configMap)).To(Succeed())
+			Expect(reconciler.Reconcile()).To(Succeed())
+			Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+			Expect(gate.Status.ConditionTrueSince).To(BeNil())
+		})
 	})
 
 	Describe("FetchGateTargetObjects", func() {
@@ -1336,6 +1473,23 @@ var _ = Describe("GateCommonReconciler", func() {
 		})
 	})
 
+	Describe("GetFailingObjects", func() {
+		It("should cap the failing objects", func() {
+			objects := make([]unstructured.Unstructured, gateshv1alpha1.GateTargetFailingObjectsMaxEntries+5)
+			failures := make([][]gateshv1alpha1.GateTargetValidatorFailure, len(objects))
+			for idx := range objects {
+				objects[idx].SetName(fmt.Sprintf("pod%d", idx))
+				if idx > 0 {
+					failures[idx] = []gateshv1alpha1.GateTargetValidatorFailure{{Validator: gateshv1alpha1.GateValidatorJsonPointer, Message: "wrong"}}
+				}
+			}
+
+			failingObjects := GetFailingObjects(objects, failures)
+			Expect(failingObjects).To(HaveLen(gateshv1alpha1.GateTargetFailingObjectsMaxEntries))
+			Expect(failingObjects[0].Name).To(Equal("pod1"))
+		})
+	})
+
 	Describe("GetObjectStatusConditions", func() {
 		It("should extract conditions from unstructured object", func() {
 			obj := &unstructured.Unstructured{
@@ -1475,7 +1629,7 @@ var _ = Describe("GateCommonReconciler", func() {
 				{Status: metav1.ConditionTrue},
 			}
 			result := reconciler.ComputeOperation(conditions)
-			Expect(result).To(BeTrue())
+			Expect(result).To(Equal(metav1.ConditionTrue))
 		})
 
 		It("should return false for AND with one false condition", func() {
@@ -1484,7 +1638,7 @@ var _ = Describe("GateCommonReconciler", func() {
 				{Status: metav1.ConditionFalse},
 			}
 			result := reconciler.ComputeOperation(conditions)
-			Expect(result).To(BeFalse())
+			Expect(result).To(Equal(metav1.ConditionFalse))
 		})
 
 		It("should return true for OR with one true condition", func() {
@@ -1494,7 +1648,7 @@ var _ = Describe("GateCommonReconciler", func() {
 				{Status: metav1.ConditionTrue},
 			}
 			result := reconciler.ComputeOperation(conditions)
-			Expect(result).To(BeTrue())
+			Expect(result).To(Equal(metav1.ConditionTrue))
 		})
 
 		It("should return false for OR with all false conditions", func() {
@@ -1504,7 +1658,7 @@ var _ = Describe("GateCommonReconciler", func() {
 				{Status: metav1.ConditionFalse},
 			}
 			result := reconciler.ComputeOperation(conditions)
-			Expect(result).To(BeFalse())
+			Expect(result).To(Equal(metav1.ConditionFalse))
 		})
 
 		It("should invert the result if Invert is true", func() {
@@ -1513,7 +1667,38 @@ var _ = Describe("GateCommonReconciler", func() {
 				{Status: metav1.ConditionTrue},
 			}
 			result := reconciler.ComputeOperation(conditions)
-			Expect(result).To(BeFalse())
+			Expect(result).To(Equal(metav1.ConditionFalse))
+		})
+
+		It("should return unknown for AND with an unknown condition and no false condition", func() {
+			conditions := []metav1.Condition{
+				{Status: metav1.ConditionTrue},
+				{Status: metav1.ConditionUnknown},
+			}
+			Expect(reconciler.ComputeOperation(conditions)).To(Equal(metav1.ConditionUnknown))
+
+			conditions = append(conditions, metav1.Condition{Status: metav1.ConditionFalse})
+			Expect(reconciler.ComputeOperation(conditions)).To(Equal(metav1.ConditionFalse))
+		})
+
+		It("should return unknown for OR with an unknown condition and no true condition", func() {
+			reconciler.Gate.Spec.Operation.Operator = gateshv1alpha1.GateOperatorOr
+			conditions := []metav1.Condition{
+				{Status: metav1.ConditionFalse},
+				{Status: metav1.ConditionUnknown},
+			}
+			Expect(reconciler.ComputeOperation(conditions)).To(Equal(metav1.ConditionUnknown))
+
+			conditions = append(conditions, metav1.Condition{Status: metav1.ConditionTrue})
+			Expect(reconciler.ComputeOperation(conditions)).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("should not invert an unknown result", func() {
+			reconciler.Gate.Spec.Operation.Invert = true
+			conditions := []metav1.Condition{
+				{Status: metav1.ConditionUnknown},
+			}
+			Expect(reconciler.ComputeOperation(conditions)).To(Equal(metav1.ConditionUnknown))
 		})
 	})
 
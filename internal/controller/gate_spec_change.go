@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"fmt"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// HandleSpecChange restarts the consolidation of the gate when its spec changed since the last evaluation, so
+// that the new spec is consolidated on its own evaluations. An opened gate keeps its valid evaluations with the
+// KeepOpened policy, and has to meet its consolidation again with the Reconsolidate policy.
+func (g *GateCommonReconciler) HandleSpecChange() {
+	g.Reconsolidating = false
+	status := &g.Gate.Status
+	if status.LastEvaluatedGeneration == 0 || status.LastEvaluatedGeneration == g.Gate.Generation {
+		return
+	}
+
+	opened := status.State == gateshv1alpha1.GateStateOpened
+	g.Reconsolidating = opened && g.Gate.Spec.SpecChangePolicy == gateshv1alpha1.GateSpecChangePolicyReconsolidate
+	logf.FromContext(g.Context).Info(fmt.Sprintf("%s %s spec changed, consolidation restarted", g.Gate.Kind, g.Gate.Name),
+		"lastEvaluatedGeneration", status.LastEvaluatedGeneration, "generation", g.Gate.Generation, "reconsolidating", g.Reconsolidating)
+
+	status.ConsecutiveInvalidEvaluations = 0
+	if !opened || g.Reconsolidating {
+		status.ConsecutiveValidEvaluations = 0
+		status.ConditionTrueSince = nil
+	}
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateSpecChange", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should restart the consolidation of a closed gate when its spec changes", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", Generation: 1},
+			Spec: gateshv1alpha1.GateSpec{
+				Consolidation: gateshv1alpha1.GateConsolidation{Count: 2},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.ConsecutiveValidEvaluations).To(Equal(1))
+
+		By("not carrying the valid evaluations over to the new spec")
+		gate.Generation = 2
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.ConsecutiveValidEvaluations).To(Equal(1))
+		Expect(gate.Status.ObservedGeneration).To(Equal(int64(2)))
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+	})
+
+	It("should keep an opened gate opened when its spec changes with the KeepOpened policy", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", Generation: 1},
+			Spec: gateshv1alpha1.GateSpec{
+				Consolidation: gateshv1alpha1.GateConsolidation{Count: 2},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		gate.Generation = 2
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(gate.Status.ConsecutiveValidEvaluations).To(Equal(2))
+		Expect(gate.Status.History).To(HaveLen(2))
+	})
+
+	It("should reconsolidate an opened gate when its spec changes with the Reconsolidate policy", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", Generation: 1},
+			Spec: gateshv1alpha1.GateSpec{
+				Consolidation:    gateshv1alpha1.GateConsolidation{Count: 2},
+				SpecChangePolicy: gateshv1alpha1.GateSpecChangePolicyReconsolidate,
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		By("closing the gate until it meets its consolidation again")
+		gate.Generation = 2
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.ConsecutiveValidEvaluations).To(Equal(1))
+		Expect(reconciler.Reconsolidating).To(BeTrue())
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(reconciler.Reconsolidating).To(BeFalse())
+	})
+
+	It("should observe the spec changes of a suspended gate once it's evaluated again", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default", Generation: 1},
+			Spec: gateshv1alpha1.GateSpec{
+				Consolidation: gateshv1alpha1.GateConsolidation{Count: 2},
+				Timeout:       &metav1.Duration{Duration: time.Hour},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		timeoutStartTime := gate.Status.TimeoutStartTime
+
+		By("observing the generation without evaluating it while suspended")
+		gate.Generation = 2
+		gate.Spec.Suspend = true
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.ObservedGeneration).To(Equal(int64(2)))
+		Expect(gate.Status.LastEvaluatedGeneration).To(Equal(int64(1)))
+		Expect(gate.Status.ConsecutiveValidEvaluations).To(Equal(1))
+		Expect(gate.Status.TimeoutStartTime).To(Equal(timeoutStartTime))
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionReady).ObservedGeneration).To(Equal(int64(2)))
+
+		By("restarting the consolidation and the timeout once resumed")
+		gate.Generation = 3
+		gate.Spec.Suspend = false
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.ObservedGeneration).To(Equal(int64(3)))
+		Expect(gate.Status.LastEvaluatedGeneration).To(Equal(int64(3)))
+		Expect(gate.Status.ConsecutiveValidEvaluations).To(Equal(1))
+		Expect(gate.Status.TimeoutStartTime.After(timeoutStartTime.Time)).To(BeTrue())
+	})
+})
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const GateTimedOutReason = "TimedOut"
+
+// StartTimeout sets the time the timeout of the gate starts from: its creation, or the last change of its spec.
+func (g *GateCommonReconciler) StartTimeout() {
+	status := &g.Gate.Status
+	if g.Gate.Spec.Timeout == nil {
+		status.TimeoutStartTime = nil
+		return
+	}
+	specChanged := status.LastEvaluatedGeneration != 0 && status.LastEvaluatedGeneration != g.Gate.Generation
+	if status.TimeoutStartTime != nil && !specChanged {
+		return
+	}
+	start := metav1.Now()
+	if !specChanged && status.LastEvaluatedGeneration == 0 && !g.Gate.CreationTimestamp.IsZero() {
+		start = g.Gate.CreationTimestamp
+	}
+	status.TimeoutStartTime = &start
+}
+
+// GetTimeoutRemaining returns the time left for the gate to open before it fails. The gate can't time out if it
+// opened since the start of its timeout.
+func (g *GateCommonReconciler) GetTimeoutRemaining(now time.Time) (time.Duration, bool) {
+	status := &g.Gate.Status
+	if g.Gate.Spec.Timeout == nil || status.TimeoutStartTime == nil || status.State == gateshv1alpha1.GateStateOpened {
+		return 0, false
+	}
+	if status.LastOpenedTime != nil && !status.LastOpenedTime.Before(status.TimeoutStartTime) {
+		return 0, false
+	}
+	return status.TimeoutStartTime.Add(g.Gate.Spec.Timeout.Duration).Sub(now), true
+}
+
+func (g *GateCommonReconciler) IsEvaluationStopped() bool {
+	if g.Gate.Status.State != gateshv1alpha1.GateStateFailed || g.Gate.Spec.TimeoutPolicy != gateshv1alpha1.GateTimeoutPolicyStop {
+		return false
+	}
+	remaining, applies := g.GetTimeoutRemaining(time.Now())
+	return applies && remaining <= 0
+}
+
+// ApplyTimeout fails the gate if it didn't open before its timeout. Until then, the gate is reconciled again when the
+// timeout expires. A failed gate is not evaluated anymore or only every evaluation period, depending on the policy.
+func (g *GateCommonReconciler) ApplyTimeout() {
+	remaining, applies := g.GetTimeoutRemaining(time.Now())
+	if !applies || remaining > 0 {
+		meta.RemoveStatusCondition(&g.Gate.Status.Conditions, gateshv1alpha1.GateConditionTimedOut)
+		if applies {
+			g.ShortenRequeueAfter(remaining)
+		}
+		return
+	}
+
+	message := fmt.Sprintf("Gate did not open within %s", g.Gate.Spec.Timeout.Duration)
+	g.Gate.Status.State = gateshv1alpha1.GateStateFailed
+	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateStateOpened, Status: metav1.ConditionFalse, Reason: GateTimedOutReason, Message: message})
+	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateStateClosed, Status: metav1.ConditionTrue, Reason: GateTimedOutReason, Message: message})
+	meta.SetStatusCondition(&g.Gate.Status.Conditions, metav1.Condition{Type: gateshv1alpha1.GateConditionTimedOut, Status: metav1.ConditionTrue, Reason: GateTimedOutReason, Message: message})
+	g.VerboseLog("Timeout exceeded", "timeoutStartTime", g.Gate.Status.TimeoutStartTime, "timeout", g.Gate.Spec.Timeout.Duration.String(), "policy", g.Gate.Spec.TimeoutPolicy)
+
+	g.RequeueAfter = 0
+	if g.Gate.Spec.TimeoutPolicy == gateshv1alpha1.GateTimeoutPolicySlow {
+		g.RequeueAfter = g.Gate.Spec.EvaluationPeriod.Duration
+	}
+}
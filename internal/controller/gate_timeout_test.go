@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateTimeout", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	expire := func(gate *gateshv1alpha1.Gate) {
+		start := metav1.NewTime(gate.Status.TimeoutStartTime.Add(-time.Hour))
+		gate.Status.TimeoutStartTime = &start
+	}
+
+	It("should fail the gate once the timeout is exceeded and stop evaluating it", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-gate",
+				Namespace:         "default",
+				Generation:        1,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+			},
+			Spec: gateshv1alpha1.GateSpec{
+				Timeout:          &metav1.Duration{Duration: 10 * time.Minute},
+				EvaluationPeriod: &metav1.Duration{Duration: time.Hour},
+				Consolidation:    gateshv1alpha1.GateConsolidation{Delay: &metav1.Duration{Duration: time.Hour}},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		recorder := record.NewFakeRecorder(20)
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate, GateOutputs: GateOutputs{Recorder: NewGateEventRecorder(recorder)}}
+
+		By("requeuing the gate when the timeout expires")
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(gate.Status.TimeoutStartTime.Time).To(Equal(gate.CreationTimestamp.Time))
+		Expect(reconciler.RequeueAfter).To(BeNumerically("~", 5*time.Minute, time.Second))
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionTimedOut)).To(BeNil())
+
+		By("failing the gate")
+		expire(gate)
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+		Expect(meta.IsStatusConditionTrue(gate.Status.Conditions, gateshv1alpha1.GateConditionTimedOut)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(gate.Status.Conditions, gateshv1alpha1.GateConditionStalled)).To(BeTrue())
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateStateOpened).Reason).To(Equal(GateTimedOutReason))
+		Expect(reconciler.RequeueAfter).To(BeZero())
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventReasonClosed)))
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventReasonTimedOut)))
+
+		By("not evaluating the failed gate anymore")
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		lastEvaluationTime := gate.Status.LastEvaluationTime
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+		Expect(gate.Status.LastEvaluationTime).To(Equal(lastEvaluationTime))
+
+		By("evaluating the gate again once its spec changed")
+		gate.Generation = 2
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(meta.FindStatusCondition(gate.Status.Conditions, gateshv1alpha1.GateConditionTimedOut)).To(BeNil())
+	})
+
+	It("should keep evaluating a failed gate with the Slow policy", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-gate",
+				Namespace:         "default",
+				Generation:        1,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+			},
+			Spec: gateshv1alpha1.GateSpec{
+				Timeout:          &metav1.Duration{Duration: 10 * time.Minute},
+				TimeoutPolicy:    gateshv1alpha1.GateTimeoutPolicySlow,
+				EvaluationPeriod: &metav1.Duration{Duration: time.Hour},
+				Consolidation:    gateshv1alpha1.GateConsolidation{Delay: &metav1.Duration{Duration: time.Hour}},
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		expire(gate)
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateFailed))
+		Expect(reconciler.RequeueAfter).To(Equal(time.Hour))
+
+		By("opening the gate once valid")
+		Expect(cl.Create(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateOpened))
+
+		By("not failing the gate closing after its opening")
+		Expect(cl.Delete(ctx, configMap)).To(Succeed())
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.State).To(Equal(gateshv1alpha1.GateStateClosed))
+	})
+})
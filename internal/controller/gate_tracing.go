@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"github.com/robinlioret/gate-operator/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartSpan starts a span, child of the current span of the reconciler. The context of the reconciler is replaced by
+// the context of the span until the returned function ends the span.
+func (g *GateCommonReconciler) StartSpan(name string, attributes ...attribute.KeyValue) (trace.Span, func()) {
+	parent := g.Context
+	ctx, span := tracing.StartSpan(parent, name, attributes...)
+	g.Context = ctx
+	return span, func() {
+		span.End()
+		g.Context = parent
+	}
+}
+
+func (g *GateCommonReconciler) GetSpanAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("gate.kind", g.GetGateKind()),
+		attribute.String("gate.namespace", g.Gate.Namespace),
+		attribute.String("gate.name", g.Gate.Name),
+	}
+}
+
+func CountValidObjects(results []bool) int {
+	count := 0
+	for _, result := range results {
+		if result {
+			count++
+		}
+	}
+	return count
+}
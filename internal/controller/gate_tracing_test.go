@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateTracing", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var recorder *tracetest.SpanRecorder
+	var previousProvider trace.TracerProvider
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		previousProvider = otel.GetTracerProvider()
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+
+	AfterEach(func() {
+		otel.SetTracerProvider(previousProvider)
+	})
+
+	getSpans := func(name string) []sdktrace.ReadOnlySpan {
+		var spans []sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.Name() == name {
+				spans = append(spans, span)
+			}
+		}
+		return spans
+	}
+
+	It("should trace the evaluation of the gate", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						Validators: []gateshv1alpha1.GateTargetValidator{
+							{JsonPointer: gateshv1alpha1.GateTargetValidatorJsonPointer{Pointer: "/data/ready", Value: "true"}},
+						},
+					},
+					{
+						Name: "Invalid",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "invalid/api/version",
+							Kind:       "Invalid",
+							Name:       "invalid",
+						},
+					},
+				},
+			},
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"ready": "true"},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.Context).To(Equal(ctx))
+
+		reconcileSpans := getSpans("Reconcile")
+		Expect(reconcileSpans).To(HaveLen(1))
+		reconcileSpan := reconcileSpans[0]
+		Expect(reconcileSpan.Attributes()).To(ContainElements(
+			attribute.String("gate.kind", "Gate"),
+			attribute.String("gate.namespace", "default"),
+			attribute.String("gate.name", "test-gate"),
+			attribute.Bool("gate.result", false),
+			attribute.String("gate.state", gateshv1alpha1.GateStateClosed),
+		))
+
+		targetSpans := getSpans("EvaluateTarget")
+		Expect(targetSpans).To(HaveLen(2))
+		for _, span := range targetSpans {
+			Expect(span.Parent().SpanID()).To(Equal(reconcileSpan.SpanContext().SpanID()))
+		}
+		Expect(targetSpans[0].Attributes()).To(ContainElements(
+			attribute.String("gate.target", "Config"),
+			attribute.String("k8s.kind", "ConfigMap"),
+			attribute.Int("gate.objects.count", 1),
+			attribute.Int("gate.objects.valid", 1),
+			attribute.String("gate.target.status", string(metav1.ConditionTrue)),
+		))
+		Expect(targetSpans[1].Status().Code).To(Equal(codes.Error))
+
+		fetchSpans := getSpans("FetchGateTargetObjects")
+		Expect(fetchSpans).To(HaveLen(2))
+		Expect(fetchSpans[0].Parent().SpanID()).To(Equal(targetSpans[0].SpanContext().SpanID()))
+		Expect(fetchSpans[0].Attributes()).To(ContainElement(attribute.Int("gate.objects.count", 1)))
+		Expect(fetchSpans[1].Status().Code).To(Equal(codes.Error))
+
+		validatorSpans := getSpans("EvaluateTargetJsonPointer")
+		Expect(validatorSpans).To(HaveLen(1))
+		Expect(validatorSpans[0].Attributes()).To(ContainElements(
+			attribute.String("validator.jsonPointer.pointer", "/data/ready"),
+			attribute.Int("gate.objects.valid", 1),
+		))
+		Expect(getSpans("ComputeTargetEvaluationResult")).To(HaveLen(1))
+	})
+})
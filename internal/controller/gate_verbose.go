@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func (g *GateCommonReconciler) GetDebugMode() string {
+	switch mode := g.Gate.GetAnnotations()[gateshv1alpha1.GateDebugAnnotation]; mode {
+	case gateshv1alpha1.GateDebugLogs, gateshv1alpha1.GateDebugStatus:
+		return mode
+	default:
+		return ""
+	}
+}
+
+// VerboseLog logs the details of the evaluation of a gate with the gate.sh/debug annotation. The logs are emitted at
+// the info level, so that they are visible without raising the verbosity of the whole operator.
+func (g *GateCommonReconciler) VerboseLog(message string, keysAndValues ...any) {
+	mode := g.GetDebugMode()
+	if mode == "" {
+		return
+	}
+	logf.FromContext(g.Context).Info(message, append([]any{"verbose", true}, keysAndValues...)...)
+	if mode == gateshv1alpha1.GateDebugStatus {
+		g.VerboseTrace = append(g.VerboseTrace, FormatVerboseEntry(message, keysAndValues))
+	}
+}
+
+func (g *GateCommonReconciler) VerboseLogFetchedObjects(target *gateshv1alpha1.GateTarget, objects []unstructured.Unstructured, err error) {
+	if g.GetDebugMode() == "" {
+		return
+	}
+	if err != nil {
+		g.VerboseLog("Target objects not fetched", "target", target.Name, "error", err.Error())
+		return
+	}
+	g.VerboseLog("Target objects fetched", "target", target.Name, "apiVersion", target.Selector.ApiVersion,
+		"kind", target.Selector.Kind, "count", len(objects))
+	for _, object := range objects {
+		g.VerboseLog("Object fetched", "target", target.Name, "object", g.GetObjectName(object),
+			"resourceVersion", object.GetResourceVersion(), "generation", object.GetGeneration())
+	}
+}
+
+// UpdateStatusDebug writes the verbose trace of the evaluation to the status when the gate.sh/debug annotation is
+// "status", and removes it otherwise.
+func (g *GateCommonReconciler) UpdateStatusDebug() {
+	if g.GetDebugMode() != gateshv1alpha1.GateDebugStatus {
+		g.Gate.Status.Debug = ""
+		return
+	}
+	g.Gate.Status.Debug = TruncateVerboseTrace(g.VerboseTrace, gateshv1alpha1.GateStatusDebugMaxLength)
+}
+
+func FormatVerboseEntry(message string, keysAndValues []any) string {
+	var builder strings.Builder
+	builder.WriteString(message)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		_, _ = fmt.Fprintf(&builder, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+	}
+	return builder.String()
+}
+
+func TruncateVerboseTrace(trace []string, maxLength int) string {
+	const marker = "... truncated"
+	debug := strings.Join(trace, "\n")
+	if len(debug) <= maxLength {
+		return debug
+	}
+	debug = debug[:maxLength-len(marker)]
+	if idx := strings.LastIndex(debug, "\n"); idx >= 0 {
+		debug = debug[:idx+1]
+	}
+	return debug + marker
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("GateVerbose", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should not trace the gates without the annotation", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						Validators: []gateshv1alpha1.GateTargetValidator{
+							{JsonPointer: gateshv1alpha1.GateTargetValidatorJsonPointer{Pointer: "/data/ready", Value: "true"}},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"ready": "false"},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.VerboseTrace).To(BeEmpty())
+		Expect(gate.Status.Debug).To(BeEmpty())
+	})
+
+	It("should only log the evaluation details when the annotation is true", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-gate",
+				Namespace:   "default",
+				Annotations: map[string]string{gateshv1alpha1.GateDebugAnnotation: gateshv1alpha1.GateDebugLogs},
+			},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						Validators: []gateshv1alpha1.GateTargetValidator{
+							{JsonPointer: gateshv1alpha1.GateTargetValidatorJsonPointer{Pointer: "/data/ready", Value: "true"}},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"ready": "false"},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(reconciler.GetDebugMode()).To(Equal(gateshv1alpha1.GateDebugLogs))
+		Expect(reconciler.VerboseTrace).To(BeEmpty())
+		Expect(gate.Status.Debug).To(BeEmpty())
+	})
+
+	It("should write the evaluation details to the status", func() {
+		gate := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-gate",
+				Namespace:   "default",
+				Annotations: map[string]string{gateshv1alpha1.GateDebugAnnotation: gateshv1alpha1.GateDebugStatus},
+			},
+			Spec: gateshv1alpha1.GateSpec{
+				Targets: []gateshv1alpha1.GateTarget{
+					{
+						Name: "Config",
+						Selector: gateshv1alpha1.GateTargetSelector{
+							ApiVersion: "v1",
+							Kind:       "ConfigMap",
+							Name:       "config",
+						},
+						Validators: []gateshv1alpha1.GateTargetValidator{
+							{JsonPointer: gateshv1alpha1.GateTargetValidatorJsonPointer{Pointer: "/data/ready", Value: "true"}},
+						},
+					},
+				},
+			},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+			Data:       map[string]string{"ready": "false"},
+		}
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, configMap).Build()
+		reconciler := GateCommonReconciler{Context: ctx, Client: cl, Gate: gate}
+
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Debug).To(ContainSubstring("Object fetched target=Config object=default/config"))
+		Expect(gate.Status.Debug).To(ContainSubstring("Evaluating validator validator=JsonPointer pointer=/data/ready value=true"))
+		Expect(gate.Status.Debug).To(ContainSubstring("Object rejected by the validator validator=JsonPointer object=default/config"))
+		Expect(gate.Status.Debug).To(ContainSubstring("Target threshold computed objects=1 valid=0 required=1 result=false"))
+		Expect(gate.Status.Debug).To(ContainSubstring("Consolidation computed result=False"))
+
+		By("removing the trace once the annotation is removed")
+		gate.Annotations = nil
+		Expect(reconciler.Reconcile()).To(Succeed())
+		Expect(gate.Status.Debug).To(BeEmpty())
+	})
+
+	It("should truncate the trace to the maximum length", func() {
+		trace := []string{strings.Repeat("a", 10), strings.Repeat("b", 10), strings.Repeat("c", 10)}
+		Expect(TruncateVerboseTrace(trace, 100)).To(Equal(strings.Join(trace, "\n")))
+		truncated := TruncateVerboseTrace(trace, 30)
+		Expect(truncated).To(Equal(strings.Repeat("a", 10) + "\n... truncated"))
+		Expect(len(truncated)).To(BeNumerically("<=", 30))
+	})
+})
@@ -0,0 +1,200 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the gate Prometheus metrics, registered with the controller-runtime registry.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "gate_operator"
+
+var (
+	GateState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "gate_state",
+		Help:      "State of the gate, 1 for the current state and 0 for the other ones.",
+	}, []string{"kind", "namespace", "name", "state"})
+
+	TargetStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "target_status",
+		Help:      "Status of the target condition of the gate, 1 if validated, 0 otherwise.",
+	}, []string{"kind", "namespace", "name", "target"})
+
+	TargetEvaluationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "target_evaluation_duration_seconds",
+		Help:      "Duration of the evaluation of a target, fetch of the objects included.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+	}, []string{"kind", "namespace", "name", "target"})
+
+	TransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transitions_total",
+		Help:      "Number of state transitions of the gate, by new state.",
+	}, []string{"kind", "namespace", "name", "state"})
+
+	FetchErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_errors_total",
+		Help:      "Number of failures to fetch the objects of a target, by reason.",
+	}, []string{"kind", "namespace", "name", "target", "reason"})
+
+	ObjectsEvaluatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "objects_evaluated_total",
+		Help:      "Number of objects evaluated for a target.",
+	}, []string{"kind", "namespace", "name", "target"})
+
+	LastTransitions = NewLastTransitionCollector()
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		GateState,
+		TargetStatus,
+		TargetEvaluationDuration,
+		TransitionsTotal,
+		FetchErrorsTotal,
+		ObjectsEvaluatedTotal,
+		LastTransitions,
+	)
+}
+
+// GateLabels identifies the gate in the metrics.
+type GateLabels struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (l GateLabels) AsMap() prometheus.Labels {
+	return prometheus.Labels{"kind": l.Kind, "namespace": l.Namespace, "name": l.Name}
+}
+
+// RecordGateState records the state of the gate, the status of its targets and the time of its last transition.
+func RecordGateState(gate GateLabels, state string, targets map[string]bool, lastTransitionTime time.Time) {
+	for _, s := range []string{gateshv1alpha1.GateStateOpened, gateshv1alpha1.GateStateClosed, gateshv1alpha1.GateStateFailed} {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		GateState.WithLabelValues(gate.Kind, gate.Namespace, gate.Name, s).Set(value)
+	}
+
+	// Targets removed from the spec must not be reported anymore.
+	TargetStatus.DeletePartialMatch(gate.AsMap())
+	for target, validated := range targets {
+		value := 0.0
+		if validated {
+			value = 1
+		}
+		TargetStatus.WithLabelValues(gate.Kind, gate.Namespace, gate.Name, target).Set(value)
+	}
+
+	LastTransitions.Set(gate, lastTransitionTime)
+}
+
+// RecordTransition counts a state transition of the gate and records its time.
+func RecordTransition(gate GateLabels, state string, transitionTime time.Time) {
+	TransitionsTotal.WithLabelValues(gate.Kind, gate.Namespace, gate.Name, state).Inc()
+	LastTransitions.Set(gate, transitionTime)
+}
+
+// RecordTargetEvaluation records the duration of the evaluation of a target and the number of evaluated objects.
+func RecordTargetEvaluation(gate GateLabels, target string, duration time.Duration, objects int) {
+	TargetEvaluationDuration.WithLabelValues(gate.Kind, gate.Namespace, gate.Name, target).Observe(duration.Seconds())
+	ObjectsEvaluatedTotal.WithLabelValues(gate.Kind, gate.Namespace, gate.Name, target).Add(float64(objects))
+}
+
+// RecordFetchError counts a failure to fetch the objects of a target.
+func RecordFetchError(gate GateLabels, target string, reason string) {
+	FetchErrorsTotal.WithLabelValues(gate.Kind, gate.Namespace, gate.Name, target, reason).Inc()
+}
+
+// DeleteGate removes all the metrics of a deleted gate.
+func DeleteGate(gate GateLabels) {
+	labels := gate.AsMap()
+	GateState.DeletePartialMatch(labels)
+	TargetStatus.DeletePartialMatch(labels)
+	TargetEvaluationDuration.DeletePartialMatch(labels)
+	TransitionsTotal.DeletePartialMatch(labels)
+	FetchErrorsTotal.DeletePartialMatch(labels)
+	ObjectsEvaluatedTotal.DeletePartialMatch(labels)
+	LastTransitions.Delete(gate)
+}
+
+// LastTransitionCollector reports the time elapsed since the last transition of each gate, computed at scrape time.
+type LastTransitionCollector struct {
+	desc        *prometheus.Desc
+	mutex       sync.RWMutex
+	transitions map[GateLabels]time.Time
+}
+
+func NewLastTransitionCollector() *LastTransitionCollector {
+	return &LastTransitionCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "seconds_since_last_transition"),
+			"Time elapsed since the last state transition of the gate.",
+			[]string{"kind", "namespace", "name"}, nil,
+		),
+		transitions: make(map[GateLabels]time.Time),
+	}
+}
+
+func (c *LastTransitionCollector) Set(gate GateLabels, transitionTime time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if transitionTime.IsZero() {
+		delete(c.transitions, gate)
+		return
+	}
+	c.transitions[gate] = transitionTime
+}
+
+func (c *LastTransitionCollector) Get(gate GateLabels) (time.Time, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	transitionTime, ok := c.transitions[gate]
+	return transitionTime, ok
+}
+
+func (c *LastTransitionCollector) Delete(gate GateLabels) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.transitions, gate)
+}
+
+func (c *LastTransitionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *LastTransitionCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	now := time.Now()
+	for gate, transitionTime := range c.transitions {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(transitionTime).Seconds(),
+			gate.Kind, gate.Namespace, gate.Name)
+	}
+}
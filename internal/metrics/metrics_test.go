@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+)
+
+var _ = Describe("Metrics", func() {
+	var gate GateLabels
+
+	BeforeEach(func() {
+		gate = GateLabels{Kind: "Gate", Namespace: "default", Name: "test-gate"}
+		DeferCleanup(DeleteGate, gate)
+	})
+
+	It("should record the state of the gate and of its targets", func() {
+		RecordGateState(gate, gateshv1alpha1.GateStateOpened, map[string]bool{"Database": true, "Cache": false}, time.Now().Add(-time.Minute))
+
+		Expect(testutil.ToFloat64(GateState.WithLabelValues("Gate", "default", "test-gate", "Opened"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(GateState.WithLabelValues("Gate", "default", "test-gate", "Closed"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(TargetStatus.WithLabelValues("Gate", "default", "test-gate", "Database"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(TargetStatus.WithLabelValues("Gate", "default", "test-gate", "Cache"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(LastTransitions)).To(BeNumerically("~", 60, 5))
+
+		By("removing a target from the spec")
+		RecordGateState(gate, gateshv1alpha1.GateStateOpened, map[string]bool{"Database": true}, time.Now())
+		Expect(testutil.CollectAndCount(TargetStatus)).To(Equal(1))
+	})
+
+	It("should count the transitions, the fetch errors and the evaluated objects", func() {
+		RecordTransition(gate, gateshv1alpha1.GateStateOpened, time.Now())
+		RecordTransition(gate, gateshv1alpha1.GateStateOpened, time.Now())
+		RecordFetchError(gate, "Database", "Forbidden")
+		RecordTargetEvaluation(gate, "Database", 10*time.Millisecond, 3)
+		RecordTargetEvaluation(gate, "Database", 20*time.Millisecond, 3)
+
+		Expect(testutil.ToFloat64(TransitionsTotal.WithLabelValues("Gate", "default", "test-gate", "Opened"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(FetchErrorsTotal.WithLabelValues("Gate", "default", "test-gate", "Database", "Forbidden"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(ObjectsEvaluatedTotal.WithLabelValues("Gate", "default", "test-gate", "Database"))).To(Equal(6.0))
+		Expect(testutil.CollectAndCount(TargetEvaluationDuration)).To(Equal(1))
+	})
+
+	It("should delete the metrics of a deleted gate", func() {
+		RecordGateState(gate, gateshv1alpha1.GateStateClosed, map[string]bool{"Database": false}, time.Now())
+		RecordTransition(gate, gateshv1alpha1.GateStateClosed, time.Now())
+
+		DeleteGate(gate)
+
+		Expect(testutil.CollectAndCount(GateState)).To(Equal(0))
+		Expect(testutil.CollectAndCount(TargetStatus)).To(Equal(0))
+		Expect(testutil.CollectAndCount(TransitionsTotal)).To(Equal(0))
+		Expect(testutil.CollectAndCount(LastTransitions)).To(Equal(0))
+	})
+})
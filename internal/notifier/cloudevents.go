@@ -0,0 +1,202 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	CloudEventTypeOpened        = "sh.gate.opened"
+	CloudEventTypeClosed        = "sh.gate.closed"
+	CloudEventTypeFailed        = "sh.gate.failed"
+	CloudEventTypeEvaluated     = "sh.gate.evaluated"
+	CloudEventTypeTargetChanged = "sh.gate.targetChanged"
+
+	CloudEventSpecVersion = "1.0"
+)
+
+type CloudEventMode = string
+
+const (
+	// CloudEventModeBinary sends the event data as body and the event attributes as ce-* headers.
+	CloudEventModeBinary CloudEventMode = "binary"
+	// CloudEventModeStructured sends the whole event as an application/cloudevents+json body.
+	CloudEventModeStructured CloudEventMode = "structured"
+)
+
+// GateReference identifies the gate emitting an event.
+type GateReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// GateEventData is the data of the gate CloudEvents.
+type GateEventData struct {
+	Gate                        GateReference      `json:"gate"`
+	PreviousState               string             `json:"previousState,omitempty"`
+	State                       string             `json:"state"`
+	TargetConditions            []metav1.Condition `json:"targetConditions,omitempty"`
+	ConsecutiveValidEvaluations int                `json:"consecutiveValidEvaluations"`
+	// Target is the condition of the changed target, for sh.gate.targetChanged events only.
+	Target *metav1.Condition `json:"target,omitempty"`
+}
+
+// CloudEvent is the structured mode representation of an event.
+type CloudEvent struct {
+	SpecVersion     string        `json:"specversion"`
+	ID              string        `json:"id"`
+	Source          string        `json:"source"`
+	Type            string        `json:"type"`
+	Subject         string        `json:"subject,omitempty"`
+	Time            time.Time     `json:"time"`
+	DataContentType string        `json:"datacontenttype"`
+	Data            GateEventData `json:"data"`
+}
+
+// CloudEventBufferSize is the number of events waiting to be sent, beyond which the new events are dropped.
+var CloudEventBufferSize = 1000
+
+// CloudEventMaxAttempts is the number of delivery attempts after which an event is dropped.
+var CloudEventMaxAttempts = 5
+
+var CloudEventRetryBaseDelay = time.Second
+var CloudEventRetryMaxDelay = 30 * time.Second
+
+// CloudEventEmitter sends the gate lifecycle CloudEvents to an HTTP sink. The events are queued by Emit and sent in
+// the background by Start, so that a slow sink doesn't hold the reconciliations.
+type CloudEventEmitter struct {
+	HTTPClient *http.Client
+	SinkURL    string
+	Mode       CloudEventMode
+	Source     string
+
+	queue chan CloudEvent
+}
+
+func NewCloudEventEmitter(sinkURL string, mode CloudEventMode, source string) (*CloudEventEmitter, error) {
+	if mode != CloudEventModeBinary && mode != CloudEventModeStructured {
+		return nil, fmt.Errorf("invalid CloudEvents mode %s, must be %s or %s", mode, CloudEventModeBinary, CloudEventModeStructured)
+	}
+	return &CloudEventEmitter{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		SinkURL:    sinkURL,
+		Mode:       mode,
+		Source:     source,
+		queue:      make(chan CloudEvent, CloudEventBufferSize),
+	}, nil
+}
+
+// Emit queues an event of the given type about the gate described by the data. The event is dropped if the queue is
+// full.
+func (e *CloudEventEmitter) Emit(eventType string, data GateEventData) error {
+	event := CloudEvent{
+		SpecVersion:     CloudEventSpecVersion,
+		ID:              string(uuid.NewUUID()),
+		Source:          e.Source,
+		Type:            eventType,
+		Subject:         GetCloudEventSubject(data.Gate),
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	select {
+	case e.queue <- event:
+		return nil
+	default:
+		return fmt.Errorf("CloudEvents queue is full, event %s dropped", event.ID)
+	}
+}
+
+// Start sends the queued events until the context is done. It implements the manager.Runnable interface.
+func (e *CloudEventEmitter) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-e.queue:
+			e.SendWithRetry(ctx, event)
+		}
+	}
+}
+
+// SendWithRetry sends the event, retrying with an exponential backoff until CloudEventMaxAttempts is reached. The ID
+// of the event is kept across the attempts, so the sink can deduplicate them.
+func (e *CloudEventEmitter) SendWithRetry(ctx context.Context, event CloudEvent) {
+	log := logf.FromContext(ctx)
+	delay := CloudEventRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := e.Send(ctx, event)
+		if err == nil {
+			return
+		}
+		if attempt >= CloudEventMaxAttempts {
+			log.Error(err, "unable to send CloudEvent, dropping it", "type", event.Type, "id", event.ID, "attempts", attempt)
+			return
+		}
+		log.Error(err, "unable to send CloudEvent, retrying", "type", event.Type, "id", event.ID, "attempts", attempt, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, CloudEventRetryMaxDelay)
+	}
+}
+
+// Send posts the event to the sink.
+func (e *CloudEventEmitter) Send(ctx context.Context, event CloudEvent) error {
+	if e.Mode == CloudEventModeStructured {
+		body, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		return PostJSON(ctx, e.HTTPClient, e.SinkURL, map[string]string{"Content-Type": "application/cloudevents+json"}, body)
+	}
+
+	body, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{
+		"ce-specversion": event.SpecVersion,
+		"ce-id":          event.ID,
+		"ce-source":      event.Source,
+		"ce-type":        event.Type,
+		"ce-subject":     event.Subject,
+		"ce-time":        event.Time.Format(time.RFC3339Nano),
+	}
+	return PostJSON(ctx, e.HTTPClient, e.SinkURL, headers, body)
+}
+
+// GetCloudEventSubject returns the subject of the events of a gate: gates/{namespace}/{name} or clustergates/{name}.
+func GetCloudEventSubject(gate GateReference) string {
+	if gate.Namespace == "" {
+		return fmt.Sprintf("clustergates/%s", gate.Name)
+	}
+	return fmt.Sprintf("gates/%s/%s", gate.Namespace, gate.Name)
+}
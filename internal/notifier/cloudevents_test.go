@@ -0,0 +1,153 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("CloudEventEmitter", func() {
+	var ctx context.Context
+	var server *httptest.Server
+	var lock sync.Mutex
+	var requests []receivedRequest
+	var failures int
+	var data GateEventData
+
+	getRequests := func() []receivedRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return requests
+	}
+
+	start := func(mode CloudEventMode) *CloudEventEmitter {
+		emitter, err := NewCloudEventEmitter(server.URL, mode, "gate-operator")
+		Expect(err).NotTo(HaveOccurred())
+		ctx, cancel := context.WithCancel(ctx)
+		DeferCleanup(cancel)
+		go func() { _ = emitter.Start(ctx) }()
+		return emitter
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests = nil
+		failures = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			lock.Lock()
+			defer lock.Unlock()
+			requests = append(requests, receivedRequest{Path: r.URL.Path, Headers: r.Header, Body: body})
+			if failures > 0 {
+				failures--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		DeferCleanup(server.Close)
+
+		data = GateEventData{
+			Gate:                        GateReference{Kind: "Gate", Namespace: "default", Name: "test-gate", UID: "uid"},
+			PreviousState:               gateshv1alpha1.GateStateClosed,
+			State:                       gateshv1alpha1.GateStateOpened,
+			TargetConditions:            []metav1.Condition{{Type: "Database", Status: metav1.ConditionTrue}},
+			ConsecutiveValidEvaluations: 3,
+		}
+	})
+
+	It("should send the event in binary mode", func() {
+		emitter := start(CloudEventModeBinary)
+		Expect(emitter.Emit(CloudEventTypeOpened, data)).To(Succeed())
+
+		Eventually(getRequests).Should(HaveLen(1))
+		requests := getRequests()
+		headers := requests[0].Headers
+		Expect(headers.Get("Content-Type")).To(Equal("application/json"))
+		Expect(headers.Get("ce-specversion")).To(Equal("1.0"))
+		Expect(headers.Get("ce-type")).To(Equal(CloudEventTypeOpened))
+		Expect(headers.Get("ce-source")).To(Equal("gate-operator"))
+		Expect(headers.Get("ce-subject")).To(Equal("gates/default/test-gate"))
+		Expect(headers.Get("ce-id")).NotTo(BeEmpty())
+		Expect(headers.Get("ce-time")).NotTo(BeEmpty())
+
+		var received GateEventData
+		Expect(json.Unmarshal(requests[0].Body, &received)).To(Succeed())
+		Expect(received.Gate.Name).To(Equal("test-gate"))
+		Expect(received.PreviousState).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(received.State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(received.ConsecutiveValidEvaluations).To(Equal(3))
+		Expect(received.TargetConditions).To(HaveLen(1))
+	})
+
+	It("should send the event in structured mode", func() {
+		emitter := start(CloudEventModeStructured)
+		data.Gate.Namespace = ""
+		Expect(emitter.Emit(CloudEventTypeEvaluated, data)).To(Succeed())
+
+		Eventually(getRequests).Should(HaveLen(1))
+		requests := getRequests()
+		Expect(requests[0].Headers.Get("Content-Type")).To(Equal("application/cloudevents+json"))
+		var event CloudEvent
+		Expect(json.Unmarshal(requests[0].Body, &event)).To(Succeed())
+		Expect(event.SpecVersion).To(Equal("1.0"))
+		Expect(event.Type).To(Equal(CloudEventTypeEvaluated))
+		Expect(event.Subject).To(Equal("clustergates/test-gate"))
+		Expect(event.DataContentType).To(Equal("application/json"))
+		Expect(event.Data.State).To(Equal(gateshv1alpha1.GateStateOpened))
+	})
+
+	It("should retry a failed delivery with the same event ID", func() {
+		baseDelay := CloudEventRetryBaseDelay
+		CloudEventRetryBaseDelay = 10 * time.Millisecond
+		DeferCleanup(func() { CloudEventRetryBaseDelay = baseDelay })
+		failures = 2
+
+		emitter := start(CloudEventModeBinary)
+		Expect(emitter.Emit(CloudEventTypeOpened, data)).To(Succeed())
+
+		Eventually(getRequests).Should(HaveLen(3))
+		Consistently(getRequests, 100*time.Millisecond).Should(HaveLen(3))
+		requests := getRequests()
+		Expect(requests[1].Headers.Get("ce-id")).To(Equal(requests[0].Headers.Get("ce-id")))
+		Expect(requests[2].Headers.Get("ce-id")).To(Equal(requests[0].Headers.Get("ce-id")))
+	})
+
+	It("should give up after the maximum number of attempts", func() {
+		baseDelay := CloudEventRetryBaseDelay
+		CloudEventRetryBaseDelay = 10 * time.Millisecond
+		DeferCleanup(func() { CloudEventRetryBaseDelay = baseDelay })
+		failures = CloudEventMaxAttempts + 1
+
+		emitter := start(CloudEventModeBinary)
+		Expect(emitter.Emit(CloudEventTypeOpened, data)).To(Succeed())
+
+		Eventually(getRequests).Should(HaveLen(CloudEventMaxAttempts))
+		Consistently(getRequests, 200*time.Millisecond).Should(HaveLen(CloudEventMaxAttempts))
+	})
+
+	It("should drop the events once the queue is full", func() {
+		bufferSize := CloudEventBufferSize
+		CloudEventBufferSize = 1
+		DeferCleanup(func() { CloudEventBufferSize = bufferSize })
+		emitter, err := NewCloudEventEmitter(server.URL, CloudEventModeBinary, "gate-operator")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(emitter.Emit(CloudEventTypeOpened, data)).To(Succeed())
+		Expect(emitter.Emit(CloudEventTypeClosed, data)).To(MatchError(ContainSubstring("queue is full")))
+	})
+
+	It("should reject an unknown mode", func() {
+		_, err := NewCloudEventEmitter(server.URL, "batched", "gate-operator")
+		Expect(err).To(MatchError(ContainSubstring("invalid CloudEvents mode batched")))
+	})
+})
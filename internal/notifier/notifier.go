@@ -0,0 +1,168 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notifier delivers the gate transitions to the sinks configured by the NotificationChannels.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Notification describes a gate transition.
+type Notification struct {
+	// ID identifies the transition, so the receivers can deduplicate the notifications.
+	ID               string             `json:"id"`
+	Kind             string             `json:"kind"`
+	Namespace        string             `json:"namespace,omitempty"`
+	Name             string             `json:"name"`
+	State            string             `json:"state"`
+	PreviousState    string             `json:"previousState,omitempty"`
+	TransitionTime   time.Time          `json:"transitionTime"`
+	TargetConditions []metav1.Condition `json:"targetConditions,omitempty"`
+}
+
+// GetGateName returns the name of the gate, prefixed by its namespace if relevant.
+func (n Notification) GetGateName() string {
+	if n.Namespace == "" {
+		return n.Name
+	}
+	return fmt.Sprintf("%s/%s", n.Namespace, n.Name)
+}
+
+// GetFailingTargets returns the names of the targets not validated.
+func (n Notification) GetFailingTargets() []string {
+	targets := make([]string, 0)
+	for _, condition := range n.TargetConditions {
+		if condition.Status != metav1.ConditionTrue {
+			targets = append(targets, condition.Type)
+		}
+	}
+	return targets
+}
+
+// Sink sends a notification to an external system.
+type Sink interface {
+	Send(ctx context.Context, notification Notification) error
+}
+
+// GateNotifier sends the gate notifications to the sinks of the NotificationChannels.
+type GateNotifier struct {
+	Client     client.Reader
+	HTTPClient *http.Client
+}
+
+func NewGateNotifier(reader client.Reader) *GateNotifier {
+	return &GateNotifier{
+		Client:     reader,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify sends the notification to the sink of the named NotificationChannel.
+func (n *GateNotifier) Notify(ctx context.Context, channelName string, notification Notification) error {
+	var channel gateshv1alpha1.NotificationChannel
+	if err := n.Client.Get(ctx, client.ObjectKey{Name: channelName}, &channel); err != nil {
+		return fmt.Errorf("failed to get NotificationChannel %s: %w", channelName, err)
+	}
+	sink, err := n.GetSink(ctx, &channel)
+	if err != nil {
+		return err
+	}
+	return sink.Send(ctx, notification)
+}
+
+// GetSink builds the sink configured by the channel.
+func (n *GateNotifier) GetSink(ctx context.Context, channel *gateshv1alpha1.NotificationChannel) (Sink, error) {
+	switch {
+	case channel.Spec.Webhook != nil:
+		url, err := n.ResolveURL(ctx, channel.Spec.Webhook.NotificationEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		return &WebhookSink{HTTPClient: n.HTTPClient, URL: url, Headers: channel.Spec.Webhook.Headers, BodyTemplate: channel.Spec.Webhook.BodyTemplate}, nil
+	case channel.Spec.Slack != nil:
+		url, err := n.ResolveURL(ctx, channel.Spec.Slack.NotificationEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		return &SlackSink{HTTPClient: n.HTTPClient, URL: url, Channel: channel.Spec.Slack.Channel, Username: channel.Spec.Slack.Username}, nil
+	case channel.Spec.Alertmanager != nil:
+		url, err := n.ResolveURL(ctx, channel.Spec.Alertmanager.NotificationEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		alertDuration := DefaultAlertDuration
+		if channel.Spec.Alertmanager.AlertDuration != nil {
+			alertDuration = channel.Spec.Alertmanager.AlertDuration.Duration
+		}
+		return &AlertmanagerSink{HTTPClient: n.HTTPClient, URL: url, Labels: channel.Spec.Alertmanager.Labels, AlertDuration: alertDuration}, nil
+	}
+	return nil, fmt.Errorf("NotificationChannel %s has no sink", channel.Name)
+}
+
+// ResolveURL returns the URL of the endpoint, reading it from its secret if needed.
+func (n *GateNotifier) ResolveURL(ctx context.Context, endpoint gateshv1alpha1.NotificationEndpoint) (string, error) {
+	if endpoint.URL != "" {
+		return endpoint.URL, nil
+	}
+	if endpoint.URLSecretRef == nil {
+		return "", fmt.Errorf("either url or urlSecretRef must be specified")
+	}
+
+	var secret corev1.Secret
+	ref := endpoint.URLSecretRef
+	if err := n.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	url, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)
+	}
+	return string(url), nil
+}
+
+// PostJSON sends the JSON body to the URL and fails if the response status is not a success.
+func PostJSON(ctx context.Context, httpClient *http.Client, url string, headers map[string]string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		content, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("unexpected response status %d: %s", response.StatusCode, string(content))
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	schemeBuilder "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type receivedRequest struct {
+	Path    string
+	Headers http.Header
+	Body    []byte
+}
+
+var _ = Describe("GateNotifier", func() {
+	var ctx context.Context
+	var scheme *runtime.Scheme
+	var server *httptest.Server
+	var requests []receivedRequest
+	var responseStatus int
+	var notification Notification
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(schemeBuilder.AddToScheme(scheme)).To(Succeed())
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		requests = nil
+		responseStatus = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, receivedRequest{Path: r.URL.Path, Headers: r.Header, Body: body})
+			w.WriteHeader(responseStatus)
+		}))
+		DeferCleanup(server.Close)
+
+		notification = Notification{
+			ID:             "uid-1700000000",
+			Kind:           "Gate",
+			Namespace:      "default",
+			Name:           "test-gate",
+			State:          gateshv1alpha1.GateStateClosed,
+			PreviousState:  gateshv1alpha1.GateStateOpened,
+			TransitionTime: time.Unix(1700000000, 0).UTC(),
+			TargetConditions: []metav1.Condition{
+				{Type: "Database", Status: metav1.ConditionFalse},
+				{Type: "Cache", Status: metav1.ConditionTrue},
+			},
+		}
+	})
+
+	newNotifier := func(objects ...runtime.Object) *GateNotifier {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+		return NewGateNotifier(cl)
+	}
+
+	newChannel := func(spec gateshv1alpha1.NotificationChannelSpec) *gateshv1alpha1.NotificationChannel {
+		return &gateshv1alpha1.NotificationChannel{
+			ObjectMeta: metav1.ObjectMeta{Name: "channel"},
+			Spec:       spec,
+		}
+	}
+
+	It("should send the notification as JSON to a webhook", func() {
+		channel := newChannel(gateshv1alpha1.NotificationChannelSpec{
+			Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+				NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				Headers:              map[string]string{"Authorization": "Bearer token"},
+			},
+		})
+		Expect(newNotifier(channel).Notify(ctx, "channel", notification)).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Headers.Get(NotificationIdHeader)).To(Equal("uid-1700000000"))
+		Expect(requests[0].Headers.Get("Authorization")).To(Equal("Bearer token"))
+		var received Notification
+		Expect(json.Unmarshal(requests[0].Body, &received)).To(Succeed())
+		Expect(received.Name).To(Equal("test-gate"))
+		Expect(received.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(received.TargetConditions).To(HaveLen(2))
+	})
+
+	It("should render the body template of a webhook", func() {
+		channel := newChannel(gateshv1alpha1.NotificationChannelSpec{
+			Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+				NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				BodyTemplate:         `{"gate": {{ json .GetGateName }}, "failing": {{ json .GetFailingTargets }}}`,
+			},
+		})
+		Expect(newNotifier(channel).Notify(ctx, "channel", notification)).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Body).To(MatchJSON(`{"gate": "default/test-gate", "failing": ["Database"]}`))
+	})
+
+	It("should reject a body template rendering invalid JSON", func() {
+		sink := &WebhookSink{BodyTemplate: `{"gate": {{ .Name }}}`}
+		_, err := sink.RenderBody(notification)
+		Expect(err).To(MatchError(ContainSubstring("valid JSON")))
+	})
+
+	It("should read the URL from a secret", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "gate-system"},
+			Data:       map[string][]byte{"url": []byte(server.URL)},
+		}
+		channel := newChannel(gateshv1alpha1.NotificationChannelSpec{
+			Slack: &gateshv1alpha1.NotificationChannelSlack{
+				NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{
+					URLSecretRef: &gateshv1alpha1.NotificationSecretKeyReference{Namespace: "gate-system", Name: "slack", Key: "url"},
+				},
+				Channel: "#deployments",
+			},
+		})
+		Expect(newNotifier(channel, secret).Notify(ctx, "channel", notification)).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		var message SlackMessage
+		Expect(json.Unmarshal(requests[0].Body, &message)).To(Succeed())
+		Expect(message.Channel).To(Equal("#deployments"))
+		Expect(message.Text).To(ContainSubstring("Gate *default/test-gate* is now *Closed*"))
+		Expect(message.Text).To(ContainSubstring("Failing targets: Database"))
+	})
+
+	It("should fire an alert when the gate closes and resolve it when the gate opens", func() {
+		channel := newChannel(gateshv1alpha1.NotificationChannelSpec{
+			Alertmanager: &gateshv1alpha1.NotificationChannelAlertmanager{
+				NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+				Labels:               map[string]string{"severity": "warning"},
+			},
+		})
+		gateNotifier := newNotifier(channel)
+		Expect(gateNotifier.Notify(ctx, "channel", notification)).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Path).To(Equal("/api/v2/alerts"))
+		var alerts []AlertmanagerAlert
+		Expect(json.Unmarshal(requests[0].Body, &alerts)).To(Succeed())
+		Expect(alerts).To(HaveLen(1))
+		Expect(alerts[0].Labels).To(HaveKeyWithValue("alertname", "GateClosed"))
+		Expect(alerts[0].Labels).To(HaveKeyWithValue("gate", "test-gate"))
+		Expect(alerts[0].Labels).To(HaveKeyWithValue("severity", "warning"))
+		Expect(alerts[0].StartsAt.Equal(notification.TransitionTime)).To(BeTrue())
+		Expect(alerts[0].EndsAt.Equal(notification.TransitionTime.Add(DefaultAlertDuration))).To(BeTrue())
+
+		notification.State = gateshv1alpha1.GateStateOpened
+		Expect(gateNotifier.Notify(ctx, "channel", notification)).To(Succeed())
+		Expect(requests).To(HaveLen(2))
+		alerts = nil
+		Expect(json.Unmarshal(requests[1].Body, &alerts)).To(Succeed())
+		Expect(alerts[0].StartsAt).To(BeNil())
+		Expect(alerts[0].EndsAt.Equal(notification.TransitionTime)).To(BeTrue())
+	})
+
+	It("should fail when the sink doesn't answer with a success", func() {
+		responseStatus = http.StatusInternalServerError
+		channel := newChannel(gateshv1alpha1.NotificationChannelSpec{
+			Webhook: &gateshv1alpha1.NotificationChannelWebhook{
+				NotificationEndpoint: gateshv1alpha1.NotificationEndpoint{URL: server.URL},
+			},
+		})
+		err := newNotifier(channel).Notify(ctx, "channel", notification)
+		Expect(err).To(MatchError(ContainSubstring("unexpected response status 500")))
+	})
+
+	It("should fail when the channel doesn't exist", func() {
+		err := newNotifier().Notify(ctx, "missing", notification)
+		Expect(err).To(MatchError(ContainSubstring("failed to get NotificationChannel missing")))
+	})
+})
@@ -0,0 +1,171 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+)
+
+// NotificationIdHeader carries the notification ID in the webhook requests.
+const NotificationIdHeader = "X-Gate-Notification-Id"
+
+var DefaultAlertDuration = 24 * time.Hour
+
+// WebhookSink posts a JSON body built from a template.
+type WebhookSink struct {
+	HTTPClient   *http.Client
+	URL          string
+	Headers      map[string]string
+	BodyTemplate string
+}
+
+func (s *WebhookSink) Send(ctx context.Context, notification Notification) error {
+	body, err := s.RenderBody(notification)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{NotificationIdHeader: notification.ID}
+	for key, value := range s.Headers {
+		headers[key] = value
+	}
+	return PostJSON(ctx, s.HTTPClient, s.URL, headers, body)
+}
+
+// RenderBody renders the body template, or encodes the notification if there is no template.
+func (s *WebhookSink) RenderBody(notification Notification) ([]byte, error) {
+	if s.BodyTemplate == "" {
+		return json.Marshal(notification)
+	}
+
+	tpl, err := template.New("body").Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			content, err := json.Marshal(value)
+			return string(content), err
+		},
+	}).Parse(s.BodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	var body bytes.Buffer
+	if err := tpl.Execute(&body, notification); err != nil {
+		return nil, fmt.Errorf("failed to render body template: %w", err)
+	}
+	if !json.Valid(body.Bytes()) {
+		return nil, fmt.Errorf("body template didn't render valid JSON")
+	}
+	return body.Bytes(), nil
+}
+
+// SlackSink posts a message to a Slack-compatible incoming webhook.
+type SlackSink struct {
+	HTTPClient *http.Client
+	URL        string
+	Channel    string
+	Username   string
+}
+
+type SlackMessage struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+func (s *SlackSink) Send(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(SlackMessage{
+		Text:     s.FormatText(notification),
+		Channel:  s.Channel,
+		Username: s.Username,
+	})
+	if err != nil {
+		return err
+	}
+	return PostJSON(ctx, s.HTTPClient, s.URL, nil, body)
+}
+
+func (s *SlackSink) FormatText(notification Notification) string {
+	icon := ":no_entry:"
+	if notification.State == gateshv1alpha1.GateStateOpened {
+		icon = ":white_check_mark:"
+	}
+	text := fmt.Sprintf("%s %s *%s* is now *%s*", icon, notification.Kind, notification.GetGateName(), notification.State)
+	if failing := notification.GetFailingTargets(); len(failing) > 0 {
+		text += fmt.Sprintf("\nFailing targets: %s", strings.Join(failing, ", "))
+	}
+	return text
+}
+
+// AlertmanagerSink posts alerts to the Alertmanager v2 API. A closed gate fires an alert, resolved once it opens.
+type AlertmanagerSink struct {
+	HTTPClient    *http.Client
+	URL           string
+	Labels        map[string]string
+	AlertDuration time.Duration
+}
+
+type AlertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    *time.Time        `json:"startsAt,omitempty"`
+	EndsAt      *time.Time        `json:"endsAt,omitempty"`
+}
+
+func (s *AlertmanagerSink) Send(ctx context.Context, notification Notification) error {
+	labels := map[string]string{
+		"alertname": "GateClosed",
+		"kind":      notification.Kind,
+		"gate":      notification.Name,
+	}
+	if notification.Namespace != "" {
+		labels["namespace"] = notification.Namespace
+	}
+	for key, value := range s.Labels {
+		labels[key] = value
+	}
+
+	alert := AlertmanagerAlert{
+		Labels: labels,
+		Annotations: map[string]string{
+			"summary": fmt.Sprintf("%s %s is %s", notification.Kind, notification.GetGateName(), notification.State),
+		},
+	}
+	if failing := notification.GetFailingTargets(); len(failing) > 0 {
+		alert.Annotations["description"] = fmt.Sprintf("Failing targets: %s", strings.Join(failing, ", "))
+	}
+	transitionTime := notification.TransitionTime
+	if notification.State == gateshv1alpha1.GateStateOpened {
+		alert.EndsAt = &transitionTime
+	} else {
+		endsAt := transitionTime.Add(s.AlertDuration)
+		alert.StartsAt = &transitionTime
+		alert.EndsAt = &endsAt
+	}
+
+	body, err := json.Marshal([]AlertmanagerAlert{alert})
+	if err != nil {
+		return err
+	}
+	return PostJSON(ctx, s.HTTPClient, strings.TrimSuffix(s.URL, "/")+"/api/v2/alerts", nil, body)
+}
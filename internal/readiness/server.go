@@ -0,0 +1,410 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readiness serves the state of the gates over HTTP, for the systems without access to the Kubernetes API.
+package readiness
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	GatesPath        = "/gates/"
+	ClusterGatesPath = "/clustergates/"
+
+	// DefaultWaitTimeout is the timeout of a long-polling request without timeout parameter.
+	DefaultWaitTimeout = 30 * time.Second
+	// MaxWaitTimeout is the longest timeout accepted for a long-polling request.
+	MaxWaitTimeout = 10 * time.Minute
+)
+
+// PollInterval is the interval between two reads of the gate while long-polling.
+var PollInterval = time.Second
+
+// TargetSummary is the result of a target of the gate.
+type TargetSummary struct {
+	Name     string                 `json:"name"`
+	Status   metav1.ConditionStatus `json:"status"`
+	Reason   string                 `json:"reason,omitempty"`
+	Message  string                 `json:"message,omitempty"`
+	Found    int                    `json:"found"`
+	Matched  int                    `json:"matched"`
+	Required int                    `json:"required"`
+}
+
+// GateReadiness is the body of the responses.
+type GateReadiness struct {
+	Kind               string          `json:"kind"`
+	Namespace          string          `json:"namespace,omitempty"`
+	Name               string          `json:"name"`
+	State              string          `json:"state"`
+	Opened             bool            `json:"opened"`
+	LastTransitionTime *metav1.Time    `json:"lastTransitionTime,omitempty"`
+	LastEvaluationTime *metav1.Time    `json:"lastEvaluationTime,omitempty"`
+	Targets            []TargetSummary `json:"targets"`
+}
+
+// Token is a bearer token accepted by the server.
+type Token struct {
+	Value string
+	// Namespaces of the Gates readable with the token. All the Gates and ClusterGates are readable if empty.
+	Namespaces []string
+}
+
+// CanRead returns true if the gates of the namespace are readable with the token. ClusterGates have no namespace.
+func (t Token) CanRead(namespace string) bool {
+	return len(t.Namespaces) == 0 || (namespace != "" && slices.Contains(t.Namespaces, namespace))
+}
+
+// ParseTokens reads one token per line, optionally followed by the comma-separated namespaces it's restricted to:
+// "my-token my-namespace,other-namespace".
+func ParseTokens(content string) []Token {
+	var tokens []Token
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		token := Token{Value: fields[0]}
+		if len(fields) > 1 {
+			for _, namespace := range strings.Split(fields[1], ",") {
+				if namespace != "" {
+					token.Namespaces = append(token.Namespaces, namespace)
+				}
+			}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// Options configures the readiness server.
+type Options struct {
+	// BindAddress of the server, such as :8082
+	BindAddress string
+	// Tokens accepted as bearer tokens. Bearer tokens are not checked if empty.
+	Tokens []Token
+	// CertDir is the directory holding the certificate and key of the server. The server is served over HTTP if empty.
+	CertDir  string
+	CertName string
+	KeyName  string
+	// ClientCAFile is the CA bundle verifying the client certificates. Client certificates are not required if empty.
+	ClientCAFile string
+	// TLSOpts customize the TLS configuration of the server.
+	TLSOpts []func(*tls.Config)
+}
+
+// Server serves the readiness of the gates. It implements the manager Runnable interface.
+type Server struct {
+	Reader  client.Reader
+	Options Options
+
+	// Watch streams the state changes of the gates under /watch. Disabled if nil.
+	Watch *Broadcaster
+}
+
+// NewServer checks that the options authenticate the requests, with bearer tokens or client certificates.
+func NewServer(reader client.Reader, options Options) (*Server, error) {
+	if len(options.Tokens) == 0 && options.ClientCAFile == "" {
+		return nil, errors.New("the readiness server requires bearer tokens or a client CA to authenticate the requests")
+	}
+	if options.ClientCAFile != "" && options.CertDir == "" {
+		return nil, errors.New("the readiness server requires a certificate to verify the client certificates")
+	}
+	if len(options.Tokens) > 0 && options.CertDir == "" {
+		return nil, errors.New("the readiness server requires a certificate to keep the bearer tokens private")
+	}
+	return &Server{Reader: reader, Options: options}, nil
+}
+
+// NeedLeaderElection returns false: all the replicas serve the readiness of the gates.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the readiness until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("readiness")
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	if s.Options.CertDir != "" {
+		tlsConfig, err := s.GetTLSConfig()
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = tlsConfig
+	}
+
+	listener, err := net.Listen("tcp", s.Options.BindAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.Options.BindAddress, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "unable to shut down the readiness server")
+		}
+	}()
+
+	log.Info("serving the readiness of the gates", "address", s.Options.BindAddress, "tls", server.TLSConfig != nil)
+	if server.TLSConfig != nil {
+		err = server.ServeTLS(listener, "", "")
+	} else {
+		err = server.Serve(listener)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// GetTLSConfig loads the certificate of the server and the CA of the client certificates.
+func (s *Server) GetTLSConfig() (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(
+		filepath.Join(s.Options.CertDir, s.Options.CertName),
+		filepath.Join(s.Options.CertDir, s.Options.KeyName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the readiness server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+	}
+	if s.Options.ClientCAFile != "" {
+		caBundle, err := os.ReadFile(s.Options.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no certificate found in the client CA %s", s.Options.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	for _, opt := range s.Options.TLSOpts {
+		opt(tlsConfig)
+	}
+	return tlsConfig, nil
+}
+
+// Handler returns the handler of the readiness requests.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(GatesPath, s.ServeGate)
+	mux.HandleFunc(ClusterGatesPath, s.ServeGate)
+	if s.Watch != nil {
+		mux.HandleFunc(WatchPath, s.ServeWatch)
+	}
+	return mux
+}
+
+// Authenticate returns the bearer token of the request. Requests authenticated by a client certificate, verified
+// during the TLS handshake, don't need a token and can read all the gates.
+func (s *Server) Authenticate(r *http.Request) (Token, bool) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return Token{}, true
+	}
+	value, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || value == "" {
+		return Token{}, false
+	}
+	for _, token := range s.Options.Tokens {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(token.Value)) == 1 {
+			return token, true
+		}
+	}
+	return Token{}, false
+}
+
+func (s *Server) ServeGate(w http.ResponseWriter, r *http.Request) {
+	token, ok := s.Authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	kind, key, ok := ParsePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !token.CanRead(key.Namespace) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	waitFor := r.URL.Query().Get("waitFor")
+	if waitFor != "" && waitFor != gateshv1alpha1.GateStateOpened && waitFor != gateshv1alpha1.GateStateClosed && waitFor != gateshv1alpha1.GateStateFailed {
+		http.Error(w, fmt.Sprintf("waitFor must be %s, %s or %s", gateshv1alpha1.GateStateOpened, gateshv1alpha1.GateStateClosed, gateshv1alpha1.GateStateFailed), http.StatusBadRequest)
+		return
+	}
+	timeout := DefaultWaitTimeout
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "invalid timeout", http.StatusBadRequest)
+			return
+		}
+		timeout = min(parsed, MaxWaitTimeout)
+	}
+
+	var readiness GateReadiness
+	var err error
+	if waitFor != "" {
+		readiness, err = s.WaitFor(r.Context(), kind, key, waitFor, timeout)
+	} else {
+		readiness, err = s.GetReadiness(r.Context(), kind, key)
+	}
+	if apierrors.IsNotFound(err) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusServiceUnavailable
+	if readiness.Opened {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(readiness)
+}
+
+// ParsePath returns the kind and key of the gate designated by the path.
+func ParsePath(path string) (string, client.ObjectKey, bool) {
+	if rest, found := strings.CutPrefix(path, GatesPath); found {
+		parts := strings.Split(rest, "/")
+		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			return "Gate", client.ObjectKey{Namespace: parts[0], Name: parts[1]}, true
+		}
+	}
+	if rest, found := strings.CutPrefix(path, ClusterGatesPath); found {
+		if rest != "" && !strings.Contains(rest, "/") {
+			return "ClusterGate", client.ObjectKey{Name: rest}, true
+		}
+	}
+	return "", client.ObjectKey{}, false
+}
+
+// WaitFor reads the gate until it reaches the state, the timeout expires or the request is cancelled. The last read
+// readiness is returned in any case.
+func (s *Server) WaitFor(ctx context.Context, kind string, key client.ObjectKey, state string, timeout time.Duration) (GateReadiness, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// The reads don't inherit the timeout, which must not fail the last read.
+		readiness, err := s.GetReadiness(context.WithoutCancel(ctx), kind, key)
+		// A failed gate is returned right away, for the clients to fail fast.
+		if err != nil || readiness.State == state || readiness.State == gateshv1alpha1.GateStateFailed {
+			return readiness, err
+		}
+		select {
+		case <-ctx.Done():
+			return readiness, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetReadiness reads the gate and summarizes its state.
+func (s *Server) GetReadiness(ctx context.Context, kind string, key client.ObjectKey) (GateReadiness, error) {
+	var status gateshv1alpha1.GateStatus
+	if kind == "ClusterGate" {
+		var gate gateshv1alpha1.ClusterGate
+		if err := s.Reader.Get(ctx, key, &gate); err != nil {
+			return GateReadiness{}, err
+		}
+		status = gate.Status
+	} else {
+		var gate gateshv1alpha1.Gate
+		if err := s.Reader.Get(ctx, key, &gate); err != nil {
+			return GateReadiness{}, err
+		}
+		status = gate.Status
+	}
+	return BuildGateReadiness(kind, key, status), nil
+}
+
+func BuildGateReadiness(kind string, key client.ObjectKey, status gateshv1alpha1.GateStatus) GateReadiness {
+	readiness := GateReadiness{
+		Kind:               kind,
+		Namespace:          key.Namespace,
+		Name:               key.Name,
+		State:              status.State,
+		Opened:             status.State == gateshv1alpha1.GateStateOpened,
+		LastEvaluationTime: status.LastEvaluationTime,
+		Targets:            make([]TargetSummary, 0, len(status.TargetConditions)),
+	}
+	if condition := meta.FindStatusCondition(status.Conditions, gateshv1alpha1.GateStateOpened); condition != nil {
+		readiness.LastTransitionTime = &condition.LastTransitionTime
+	}
+	for _, condition := range status.TargetConditions {
+		summary := TargetSummary{
+			Name:    condition.Type,
+			Status:  condition.Status,
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		}
+		for _, target := range status.Targets {
+			if target.Name == condition.Type {
+				summary.Found = target.Found
+				summary.Matched = target.Matched
+				summary.Required = target.Required
+			}
+		}
+		readiness.Targets = append(readiness.Targets, summary)
+	}
+	return readiness
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Server", func() {
+	var cl client.Client
+	var server *Server
+	var gate *gateshv1alpha1.Gate
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+		gate = &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "production"},
+			Status: gateshv1alpha1.GateStatus{
+				State: gateshv1alpha1.GateStateClosed,
+				TargetConditions: []metav1.Condition{
+					{Type: "Deployment", Status: metav1.ConditionFalse, Reason: "ConditionNotMet", Message: "0/1 valid objects, 1 objects found"},
+				},
+				Targets: []gateshv1alpha1.GateTargetStatus{{Name: "Deployment", Found: 1, Matched: 0, Required: 1}},
+			},
+		}
+		clusterGate := &gateshv1alpha1.ClusterGate{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Status:     gateshv1alpha1.GateStatus{State: gateshv1alpha1.GateStateOpened},
+		}
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(gate, clusterGate).WithStatusSubresource(gate).Build()
+
+		var err error
+		server, err = NewServer(cl, Options{
+			Tokens:  []Token{{Value: "secret"}, {Value: "production-secret", Namespaces: []string{"production"}}},
+			CertDir: "certs",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		PollInterval = 10 * time.Millisecond
+	})
+
+	get := func(path string, token string) (*httptest.ResponseRecorder, GateReadiness) {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, request)
+		var readiness GateReadiness
+		if recorder.Header().Get("Content-Type") == "application/json" {
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &readiness)).To(Succeed())
+		}
+		return recorder, readiness
+	}
+
+	It("should require an authentication", func() {
+		_, err := NewServer(cl, Options{})
+		Expect(err).To(HaveOccurred())
+		_, err = NewServer(cl, Options{Tokens: []Token{{Value: "secret"}}})
+		Expect(err).To(HaveOccurred())
+
+		response, _ := get("/gates/production/release", "")
+		Expect(response.Code).To(Equal(http.StatusUnauthorized))
+		response, _ = get("/gates/production/release", "wrong")
+		Expect(response.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should restrict the tokens to their namespaces", func() {
+		response, _ := get("/gates/production/release", "production-secret")
+		Expect(response.Code).To(Equal(http.StatusServiceUnavailable))
+		response, _ = get("/gates/staging/release", "production-secret")
+		Expect(response.Code).To(Equal(http.StatusForbidden))
+		response, _ = get("/clustergates/platform", "production-secret")
+		Expect(response.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("should parse the tokens and their namespaces", func() {
+		Expect(ParseTokens("secret\n\n  production-secret production,staging \n")).To(Equal([]Token{
+			{Value: "secret"},
+			{Value: "production-secret", Namespaces: []string{"production", "staging"}},
+		}))
+	})
+
+	It("should accept the requests authenticated by a client certificate", func() {
+		request := httptest.NewRequest(http.MethodGet, "/clustergates/platform", nil)
+		request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should serve the state of the gates", func() {
+		response, readiness := get("/gates/production/release", "secret")
+		Expect(response.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(readiness.State).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(readiness.Targets).To(Equal([]TargetSummary{
+			{Name: "Deployment", Status: metav1.ConditionFalse, Reason: "ConditionNotMet", Message: "0/1 valid objects, 1 objects found", Found: 1, Required: 1},
+		}))
+
+		response, readiness = get("/clustergates/platform", "secret")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(readiness.Kind).To(Equal("ClusterGate"))
+		Expect(readiness.Opened).To(BeTrue())
+
+		response, _ = get("/gates/production/missing", "secret")
+		Expect(response.Code).To(Equal(http.StatusNotFound))
+		response, _ = get("/gates/production", "secret")
+		Expect(response.Code).To(Equal(http.StatusNotFound))
+		response, _ = get("/gates/production/release?waitFor=Ready", "secret")
+		Expect(response.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should wait for the state of the gate", func() {
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			opened := gate.DeepCopy()
+			opened.Status.State = gateshv1alpha1.GateStateOpened
+			Expect(cl.Status().Update(context.Background(), opened)).To(Succeed())
+		}()
+
+		response, readiness := get("/gates/production/release?waitFor=Opened&timeout=5m", "secret")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(readiness.State).To(Equal(gateshv1alpha1.GateStateOpened))
+	})
+
+	It("should return the current state once the timeout expires", func() {
+		start := time.Now()
+		response, readiness := get("/gates/production/release?waitFor=Opened&timeout=50ms", "secret")
+		Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+		Expect(response.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(readiness.State).To(Equal(gateshv1alpha1.GateStateClosed))
+	})
+})
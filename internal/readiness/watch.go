@@ -0,0 +1,343 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/rand"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	WatchPath = "/watch"
+
+	// WatchEventStateChanged is sent when the state of a gate changes.
+	WatchEventStateChanged = "StateChanged"
+	// WatchEventDeleted is sent when a gate is deleted.
+	WatchEventDeleted = "Deleted"
+	// WatchEventReset is sent when the events following the resumed sequence are not buffered anymore: the client
+	// must read the current state of the gates again.
+	WatchEventReset = "Reset"
+
+	// MaxBufferedEvents is the number of events kept to resume the streams.
+	MaxBufferedEvents = 1000
+)
+
+// WatchHeartbeatInterval is the interval of the comments keeping the idle streams open.
+var WatchHeartbeatInterval = 15 * time.Second
+
+// GateStateEvent is a change of the state of a gate.
+type GateStateEvent struct {
+	// ID is the epoch of the broadcaster and the sequence of the event, e.g. "x7k2p9qz-42".
+	ID            string            `json:"id,omitempty"`
+	Sequence      uint64            `json:"sequence"`
+	Type          string            `json:"type"`
+	Time          time.Time         `json:"time"`
+	Kind          string            `json:"kind"`
+	Namespace     string            `json:"namespace,omitempty"`
+	Name          string            `json:"name"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	PreviousState string            `json:"previousState,omitempty"`
+	State         string            `json:"state,omitempty"`
+}
+
+// Broadcaster numbers the state changes of the gates and fans them out to the streams. The last events are kept to
+// resume the streams after a reconnection. Sequence numbers restart with the manager and differ between replicas: the
+// random epoch prefixing the event IDs tells whether an ID was issued by this broadcaster.
+type Broadcaster struct {
+	Epoch string
+
+	mutex       sync.Mutex
+	sequence    uint64
+	events      []GateStateEvent
+	subscribers map[chan GateStateEvent]struct{}
+}
+
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{Epoch: rand.String(8), subscribers: make(map[chan GateStateEvent]struct{})}
+}
+
+// Publish numbers the event and sends it to the subscribers. A subscriber too slow to receive it is disconnected,
+// and can resume from its last received event.
+func (b *Broadcaster) Publish(event GateStateEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.sequence++
+	event.Sequence = b.sequence
+	event.ID = fmt.Sprintf("%s-%d", b.Epoch, b.sequence)
+	b.events = append(b.events, event)
+	if overflow := len(b.events) - MaxBufferedEvents; overflow > 0 {
+		b.events = b.events[overflow:]
+	}
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Subscribe returns the buffered events following the sequence of the epoch and the channel of the next events. A
+// new subscriber, without epoch nor sequence, receives all the buffered events. gap is true if events following the
+// sequence are not buffered anymore, or if the epoch is not the one of this broadcaster. The returned function must be
+// called once done.
+func (b *Broadcaster) Subscribe(epoch string, since uint64) (replay []GateStateEvent, gap bool, events <-chan GateStateEvent, unsubscribe func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if (epoch != "" || since > 0) && (epoch != b.Epoch || since > b.sequence) {
+		// Sequence of another replica or of a previous manager: nothing can be resumed.
+		gap = true
+		since = b.sequence
+	}
+	for _, event := range b.events {
+		if event.Sequence > since {
+			replay = append(replay, event)
+		}
+	}
+	if since > 0 && len(b.events) > 0 && b.events[0].Sequence > since+1 {
+		gap = true
+	}
+
+	channel := make(chan GateStateEvent, 64)
+	b.subscribers[channel] = struct{}{}
+	return replay, gap, channel, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		if _, ok := b.subscribers[channel]; ok {
+			delete(b.subscribers, channel)
+			close(channel)
+		}
+	}
+}
+
+// SetupWithCache publishes the state changes of the Gates and ClusterGates seen by the informers of the cache.
+func (b *Broadcaster) SetupWithCache(ctx context.Context, c cache.Cache) error {
+	for kind, object := range map[string]client.Object{"Gate": &gateshv1alpha1.Gate{}, "ClusterGate": &gateshv1alpha1.ClusterGate{}} {
+		informer, err := c.GetInformer(ctx, object)
+		if err != nil {
+			return fmt.Errorf("failed to get the %s informer: %w", kind, err)
+		}
+		if _, err := informer.AddEventHandler(b.EventHandler(kind)); err != nil {
+			return fmt.Errorf("failed to watch the %ss: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// EventHandler publishes the state changes and deletions of the gates of the kind. The gates listed at startup are
+// not published.
+func (b *Broadcaster) EventHandler(kind string) toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObject, newObject any) {
+			previousState, _ := GetGateState(oldObject)
+			state, ok := GetGateState(newObject)
+			if !ok || state == previousState {
+				return
+			}
+			b.Publish(NewGateStateEvent(WatchEventStateChanged, kind, newObject.(client.Object), previousState, state))
+		},
+		DeleteFunc: func(object any) {
+			if tombstone, ok := object.(toolscache.DeletedFinalStateUnknown); ok {
+				object = tombstone.Obj
+			}
+			state, ok := GetGateState(object)
+			if !ok {
+				return
+			}
+			b.Publish(NewGateStateEvent(WatchEventDeleted, kind, object.(client.Object), state, ""))
+		},
+	}
+}
+
+func NewGateStateEvent(eventType string, kind string, object client.Object, previousState string, state string) GateStateEvent {
+	return GateStateEvent{
+		Type:          eventType,
+		Time:          time.Now().UTC(),
+		Kind:          kind,
+		Namespace:     object.GetNamespace(),
+		Name:          object.GetName(),
+		Labels:        object.GetLabels(),
+		PreviousState: previousState,
+		State:         state,
+	}
+}
+
+// GetGateState returns the state of a Gate or a ClusterGate.
+func GetGateState(object any) (string, bool) {
+	switch gate := object.(type) {
+	case *gateshv1alpha1.Gate:
+		return gate.Status.State, true
+	case *gateshv1alpha1.ClusterGate:
+		return gate.Status.State, true
+	}
+	return "", false
+}
+
+// WatchFilter selects the streamed events.
+type WatchFilter struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Selector  labels.Selector
+	// Token restricts the events to the gates it can read.
+	Token Token
+}
+
+func (f *WatchFilter) Matches(event GateStateEvent) bool {
+	if event.Type == WatchEventReset {
+		return true
+	}
+	if !f.Token.CanRead(event.Namespace) {
+		return false
+	}
+	if f.Kind != "" && f.Kind != event.Kind {
+		return false
+	}
+	if f.Namespace != "" && f.Namespace != event.Namespace {
+		return false
+	}
+	if f.Name != "" && f.Name != event.Name {
+		return false
+	}
+	return f.Selector == nil || f.Selector.Matches(labels.Set(event.Labels))
+}
+
+// ParseWatchFilter reads the kind, namespace, name and labelSelector query parameters.
+func ParseWatchFilter(r *http.Request) (WatchFilter, error) {
+	query := r.URL.Query()
+	filter := WatchFilter{Kind: query.Get("kind"), Namespace: query.Get("namespace"), Name: query.Get("name")}
+	if filter.Kind != "" && filter.Kind != "Gate" && filter.Kind != "ClusterGate" {
+		return filter, fmt.Errorf("kind must be Gate or ClusterGate")
+	}
+	if value := query.Get("labelSelector"); value != "" {
+		selector, err := labels.Parse(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+		filter.Selector = selector
+	}
+	return filter, nil
+}
+
+// GetResumeID returns the epoch and the sequence of the last event received by the client, from the Last-Event-ID
+// header sent by the SSE clients on reconnection, or from the since query parameter.
+func GetResumeID(r *http.Request) (string, uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("since")
+	}
+	if value == "" {
+		return "", 0, nil
+	}
+	epoch, sequence, ok := strings.Cut(value, "-")
+	if !ok || epoch == "" {
+		return "", 0, fmt.Errorf("event ID must be {epoch}-{sequence}")
+	}
+	since, err := strconv.ParseUint(sequence, 10, 64)
+	return epoch, since, err
+}
+
+// ServeWatch streams the state changes of the gates as Server-Sent Events.
+func (s *Server) ServeWatch(w http.ResponseWriter, r *http.Request) {
+	token, ok := s.Authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	filter, err := ParseWatchFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Token = token
+	epoch, since, err := GetResumeID(r)
+	if err != nil {
+		http.Error(w, "invalid event ID", http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	replay, gap, events, unsubscribe := s.Watch.Subscribe(epoch, since)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if gap {
+		replay = append([]GateStateEvent{{Type: WatchEventReset, Time: time.Now().UTC()}}, replay...)
+	}
+	for _, event := range replay {
+		if filter.Matches(event) {
+			writeWatchEvent(w, event)
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(WatchHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, _ = fmt.Fprint(w, ": heartbeat\n\n")
+		case event, open := <-events:
+			if !open {
+				// Disconnected for being too slow: the client resumes from its last event.
+				return
+			}
+			if !filter.Matches(event) {
+				continue
+			}
+			writeWatchEvent(w, event)
+		}
+		flusher.Flush()
+	}
+}
+
+func writeWatchEvent(w http.ResponseWriter, event GateStateEvent) {
+	data, _ := json.Marshal(event)
+	if event.ID != "" {
+		_, _ = fmt.Fprintf(w, "id: %s\n", event.ID)
+	}
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+}
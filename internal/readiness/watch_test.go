@@ -0,0 +1,216 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+var _ = Describe("Broadcaster", func() {
+	var broadcaster *Broadcaster
+
+	BeforeEach(func() {
+		broadcaster = NewBroadcaster()
+	})
+
+	It("should number the events and send them to the subscribers", func() {
+		_, _, events, unsubscribe := broadcaster.Subscribe("", 0)
+		defer unsubscribe()
+		broadcaster.Publish(GateStateEvent{Name: "a"})
+		broadcaster.Publish(GateStateEvent{Name: "b"})
+		Expect((<-events).Sequence).To(Equal(uint64(1)))
+		Expect((<-events).ID).To(Equal(broadcaster.Epoch + "-2"))
+	})
+
+	It("should replay the events following the resumed sequence", func() {
+		for _, name := range []string{"a", "b", "c"} {
+			broadcaster.Publish(GateStateEvent{Name: name})
+		}
+		replay, gap, _, unsubscribe := broadcaster.Subscribe(broadcaster.Epoch, 1)
+		defer unsubscribe()
+		Expect(gap).To(BeFalse())
+		Expect(replay).To(HaveLen(2))
+		Expect(replay[0].Name).To(Equal("b"))
+	})
+
+	It("should report a gap when the resumed events are not buffered anymore", func() {
+		for range MaxBufferedEvents + 10 {
+			broadcaster.Publish(GateStateEvent{Name: "a"})
+		}
+		replay, gap, _, unsubscribe := broadcaster.Subscribe(broadcaster.Epoch, 5)
+		defer unsubscribe()
+		Expect(gap).To(BeTrue())
+		Expect(replay).To(HaveLen(MaxBufferedEvents))
+
+		replay, gap, _, unsubscribe = broadcaster.Subscribe(broadcaster.Epoch, MaxBufferedEvents+100)
+		defer unsubscribe()
+		Expect(gap).To(BeTrue())
+		Expect(replay).To(BeEmpty())
+	})
+
+	It("should report a gap when resuming the events of another replica", func() {
+		for _, name := range []string{"a", "b", "c"} {
+			broadcaster.Publish(GateStateEvent{Name: name})
+		}
+		replay, gap, _, unsubscribe := broadcaster.Subscribe("other", 1)
+		defer unsubscribe()
+		Expect(gap).To(BeTrue())
+		Expect(replay).To(BeEmpty())
+	})
+
+	It("should publish the state changes and deletions of the gates", func() {
+		handler := broadcaster.EventHandler("Gate")
+		closed := &gateshv1alpha1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "production"},
+			Status:     gateshv1alpha1.GateStatus{State: gateshv1alpha1.GateStateClosed},
+		}
+		opened := closed.DeepCopy()
+		opened.Status.State = gateshv1alpha1.GateStateOpened
+
+		handler.OnUpdate(closed, closed.DeepCopy())
+		handler.OnUpdate(closed, opened)
+		handler.OnDelete(toolscache.DeletedFinalStateUnknown{Key: "production/release", Obj: opened})
+
+		replay, _, _, unsubscribe := broadcaster.Subscribe("", 0)
+		defer unsubscribe()
+		Expect(replay).To(HaveLen(2))
+		Expect(replay[0].Type).To(Equal(WatchEventStateChanged))
+		Expect(replay[0].Kind).To(Equal("Gate"))
+		Expect(replay[0].Namespace).To(Equal("production"))
+		Expect(replay[0].PreviousState).To(Equal(gateshv1alpha1.GateStateClosed))
+		Expect(replay[0].State).To(Equal(gateshv1alpha1.GateStateOpened))
+		Expect(replay[1].Type).To(Equal(WatchEventDeleted))
+	})
+})
+
+var _ = Describe("WatchFilter", func() {
+	event := GateStateEvent{Type: WatchEventStateChanged, Kind: "Gate", Namespace: "production", Name: "release",
+		Labels: map[string]string{"team": "payments"}}
+
+	It("should match the events by kind, namespace, name and labels", func() {
+		Expect((&WatchFilter{}).Matches(event)).To(BeTrue())
+		Expect((&WatchFilter{Kind: "ClusterGate"}).Matches(event)).To(BeFalse())
+		Expect((&WatchFilter{Namespace: "staging"}).Matches(event)).To(BeFalse())
+		Expect((&WatchFilter{Name: "release"}).Matches(event)).To(BeTrue())
+		selector, err := labels.Parse("team in (payments, search)")
+		Expect(err).NotTo(HaveOccurred())
+		Expect((&WatchFilter{Selector: selector}).Matches(event)).To(BeTrue())
+		Expect((&WatchFilter{Selector: labels.SelectorFromSet(labels.Set{"team": "search"})}).Matches(event)).To(BeFalse())
+		Expect((&WatchFilter{Token: Token{Namespaces: []string{"production"}}}).Matches(event)).To(BeTrue())
+		Expect((&WatchFilter{Token: Token{Namespaces: []string{"staging"}}}).Matches(event)).To(BeFalse())
+	})
+
+	It("should reject invalid parameters", func() {
+		_, err := ParseWatchFilter(httptest.NewRequest(http.MethodGet, "/watch?kind=Pod", nil))
+		Expect(err).To(HaveOccurred())
+		_, err = ParseWatchFilter(httptest.NewRequest(http.MethodGet, "/watch?labelSelector=a%20in", nil))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ServeWatch", func() {
+	var server *Server
+	var httpServer *httptest.Server
+
+	BeforeEach(func() {
+		var err error
+		server, err = NewServer(nil, Options{Tokens: []Token{{Value: "secret"}}, CertDir: "certs"})
+		Expect(err).NotTo(HaveOccurred())
+		server.Watch = NewBroadcaster()
+		httpServer = httptest.NewServer(server.Handler())
+		DeferCleanup(httpServer.Close)
+	})
+
+	open := func(ctx context.Context, query string, lastEventID string) *http.Response {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+WatchPath+query, nil)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Authorization", "Bearer secret")
+		if lastEventID != "" {
+			request.Header.Set("Last-Event-ID", lastEventID)
+		}
+		response, err := http.DefaultClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		return response
+	}
+
+	readEvent := func(reader *bufio.Reader) (id string, eventType string, event GateStateEvent) {
+		for {
+			line, err := reader.ReadString('\n')
+			Expect(err).NotTo(HaveOccurred())
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "" && eventType != "":
+				return id, eventType, event
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				eventType = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				Expect(json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event)).To(Succeed())
+			}
+		}
+	}
+
+	It("should require authentication", func() {
+		response, err := http.Get(httpServer.URL + WatchPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should stream the matching events and resume from the last event ID", func() {
+		server.Watch.Publish(GateStateEvent{Type: WatchEventStateChanged, Kind: "Gate", Namespace: "production", Name: "a"})
+		server.Watch.Publish(GateStateEvent{Type: WatchEventStateChanged, Kind: "Gate", Namespace: "staging", Name: "b"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		response := open(ctx, "?namespace=production", server.Watch.Epoch+"-1")
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(response.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+		server.Watch.Publish(GateStateEvent{Type: WatchEventStateChanged, Kind: "Gate", Namespace: "production", Name: "c",
+			State: gateshv1alpha1.GateStateOpened})
+		id, eventType, event := readEvent(bufio.NewReader(response.Body))
+		Expect(id).To(Equal(server.Watch.Epoch + "-3"))
+		Expect(eventType).To(Equal(WatchEventStateChanged))
+		Expect(event.Name).To(Equal("c"))
+		Expect(event.State).To(Equal(gateshv1alpha1.GateStateOpened))
+	})
+
+	It("should send a reset when the resumed events are lost", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		response := open(ctx, "?since=other-42", "")
+		defer response.Body.Close()
+		_, eventType, _ := readEvent(bufio.NewReader(response.Body))
+		Expect(eventType).To(Equal(WatchEventReset))
+	})
+})
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing configures the OpenTelemetry tracing of the gate evaluations.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const TracerName = "github.com/robinlioret/gate-operator"
+
+type Exporter = string
+
+const (
+	// ExporterNone disables the tracing.
+	ExporterNone Exporter = "none"
+	// ExporterOTLP exports the spans with OTLP over HTTP.
+	ExporterOTLP Exporter = "otlp"
+	// ExporterStdout writes the spans to the standard output.
+	ExporterStdout Exporter = "stdout"
+)
+
+// Options configures the tracing.
+type Options struct {
+	Exporter Exporter
+	// Endpoint of the OTLP collector, host:port. By default, the OTEL_EXPORTER_OTLP_* environment variables are used.
+	Endpoint string
+	// Insecure disables TLS towards the OTLP collector.
+	Insecure bool
+	// SamplingRatio is the ratio of the traces sampled, between 0 and 1.
+	SamplingRatio float64
+}
+
+// Setup registers the global tracer provider configured by the options. The returned function flushes and stops the
+// exporter.
+func Setup(ctx context.Context, options Options) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch options.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var clientOptions []otlptracehttp.Option
+		if options.Endpoint != "" {
+			clientOptions = append(clientOptions, otlptracehttp.WithEndpoint(options.Endpoint))
+		}
+		if options.Insecure {
+			clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, clientOptions...)
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter %s, must be %s, %s or %s", options.Exporter, ExporterNone, ExporterOTLP, ExporterStdout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", options.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", "gate-operator")))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SamplingRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span from the context. When a root span is sampled, the logger of the returned context logs its
+// trace and span IDs, kept by the logs of its child spans.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	root := !trace.SpanContextFromContext(ctx).IsValid()
+	ctx, span := otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+	spanContext := span.SpanContext()
+	if root && spanContext.IsValid() {
+		log := logf.FromContext(ctx).WithValues("traceID", spanContext.TraceID().String(), "spanID", spanContext.SpanID().String())
+		ctx = logf.IntoContext(ctx, log)
+	}
+	return ctx, span
+}
+
+// RecordError marks the span as failed if there is an error.
+func RecordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 Robin LIORET.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Tracing", func() {
+	var recorder *tracetest.SpanRecorder
+	var previousProvider trace.TracerProvider
+
+	BeforeEach(func() {
+		previousProvider = otel.GetTracerProvider()
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+
+	AfterEach(func() {
+		otel.SetTracerProvider(previousProvider)
+	})
+
+	It("should reject an unknown exporter", func() {
+		_, err := Setup(context.Background(), Options{Exporter: "zipkin"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not register a provider when disabled", func() {
+		shutdown, err := Setup(context.Background(), Options{Exporter: ExporterNone})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shutdown(context.Background())).To(Succeed())
+		_, isSdk := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+		Expect(isSdk).To(BeTrue())
+	})
+
+	It("should add the trace IDs of the root span to the logger once", func() {
+		var lines []string
+		logger := funcr.New(func(prefix, args string) { lines = append(lines, args) }, funcr.Options{})
+		ctx := logf.IntoContext(context.Background(), logger)
+
+		ctx, span := StartSpan(ctx, "test", attribute.String("gate.name", "test-gate"))
+		childCtx, child := StartSpan(ctx, "child")
+		logf.FromContext(childCtx).Info("evaluating")
+		child.End()
+		span.End()
+
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0]).To(ContainSubstring(`"traceID"="` + span.SpanContext().TraceID().String() + `"`))
+		Expect(lines[0]).To(ContainSubstring(`"spanID"="` + span.SpanContext().SpanID().String() + `"`))
+		Expect(strings.Count(lines[0], `"traceID"`)).To(Equal(1))
+		Expect(recorder.Ended()).To(HaveLen(2))
+		Expect(recorder.Ended()[1].Attributes()).To(ContainElement(attribute.String("gate.name", "test-gate")))
+	})
+
+	It("should mark the span as failed on error", func() {
+		_, span := StartSpan(context.Background(), "test")
+		RecordError(span, nil)
+		RecordError(span, errors.New("boom"))
+		span.End()
+
+		Expect(recorder.Ended()).To(HaveLen(1))
+		Expect(recorder.Ended()[0].Status().Code).To(Equal(codes.Error))
+		Expect(recorder.Ended()[0].Events()).To(HaveLen(1))
+	})
+})
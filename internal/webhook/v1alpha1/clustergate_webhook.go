@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -36,7 +37,7 @@ var clustergatelog = logf.Log.WithName("clustergate-resource")
 // SetupClusterGateWebhookWithManager registers the webhook for ClusterGate in the manager.
 func SetupClusterGateWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&gateshv1alpha1.ClusterGate{}).
-		WithValidator(&ClusterGateCustomValidator{}).
+		WithValidator(&ClusterGateCustomValidator{Reader: mgr.GetClient()}).
 		WithDefaulter(&ClusterGateCustomDefaulter{}).
 		Complete()
 }
@@ -55,7 +56,7 @@ type ClusterGateCustomDefaulter struct{}
 var _ webhook.CustomDefaulter = &ClusterGateCustomDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind ClusterGate.
-func (d *ClusterGateCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *ClusterGateCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	clustergate, ok := obj.(*gateshv1alpha1.ClusterGate)
 
 	if !ok {
@@ -63,20 +64,23 @@ func (d *ClusterGateCustomDefaulter) Default(_ context.Context, obj runtime.Obje
 	}
 	clustergatelog.Info("Defaulting for ClusterGate", "name", clustergate.GetName())
 	ApplyDefaultSpec(&clustergate.Spec)
+	RecordForcedBy(ctx, clustergate)
 	return nil
 }
 
-// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
 // Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
-// +kubebuilder:webhook:path=/validate-gate-sh-v1alpha1-clustergate,mutating=false,failurePolicy=fail,sideEffects=None,groups=gate.sh,resources=clustergates,verbs=create;update,versions=v1alpha1,name=vclustergate-v1alpha1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gate-sh-v1alpha1-clustergate,mutating=false,failurePolicy=fail,sideEffects=None,groups=gate.sh,resources=clustergates,verbs=create;update;delete,versions=v1alpha1,name=vclustergate-v1alpha1.kb.io,admissionReviewVersions=v1
 
 // ClusterGateCustomValidator struct is responsible for validating the ClusterGate resource
 // when it is created, updated, or deleted.
 //
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as this struct is used only for temporary operations and does not need to be deeply copied.
-type ClusterGateCustomValidator struct{}
+type ClusterGateCustomValidator struct {
+	// Reader is used to look for the dependents of the ClusterGate upon deletion. It must serve the WaitForIndexField index.
+	Reader client.Reader
+}
 
 var _ webhook.CustomValidator = &ClusterGateCustomValidator{}
 
@@ -87,7 +91,13 @@ func (v *ClusterGateCustomValidator) ValidateCreate(_ context.Context, obj runti
 		return nil, fmt.Errorf("expected a ClusterGate object but got %T", obj)
 	}
 	clustergatelog.Info("Validation for ClusterGate upon creation", "name", clustergate.GetName())
-	return ValidateGateSpec(&clustergate.Spec)
+	if err := ValidateGateExport(&clustergate.Spec.Export, ""); err != nil {
+		return nil, err
+	}
+	if err := ValidateGateAnnotations(clustergate); err != nil {
+		return nil, err
+	}
+	return ValidateGateSpec(&clustergate.Spec, "")
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterGate.
@@ -97,7 +107,13 @@ func (v *ClusterGateCustomValidator) ValidateUpdate(_ context.Context, oldObj, n
 		return nil, fmt.Errorf("expected a ClusterGate object for the newObj but got %T", newObj)
 	}
 	clustergatelog.Info("Validation for ClusterGate upon update", "name", clustergate.GetName())
-	return ValidateGateSpec(&clustergate.Spec)
+	if err := ValidateGateExport(&clustergate.Spec.Export, ""); err != nil {
+		return nil, err
+	}
+	if err := ValidateGateAnnotations(clustergate); err != nil {
+		return nil, err
+	}
+	return ValidateGateSpec(&clustergate.Spec, "")
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterGate.
@@ -107,8 +123,5 @@ func (v *ClusterGateCustomValidator) ValidateDelete(ctx context.Context, obj run
 		return nil, fmt.Errorf("expected a ClusterGate object but got %T", obj)
 	}
 	clustergatelog.Info("Validation for ClusterGate upon deletion", "name", clustergate.GetName())
-
-	// TODO(user): fill in your validation logic upon object deletion.
-
-	return nil, nil
+	return ValidateGateDeletion(ctx, v.Reader, "ClusterGate", clustergate)
 }
@@ -21,7 +21,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
-	// TODO (user): Add any additional imports if needed
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 var _ = Describe("ClusterGate Webhook", func() {
@@ -62,26 +62,21 @@ var _ = Describe("ClusterGate Webhook", func() {
 	})
 
 	Context("When creating or updating ClusterGate under Validating Webhook", func() {
-		// TODO (user): Add logic for validating webhooks
-		// Example:
-		// It("Should deny creation if a required field is missing", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = ""
-		//     Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
-		// })
-		//
-		// It("Should admit creation if all required fields are present", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = "valid_value"
-		//     Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
-		// })
-		//
-		// It("Should validate updates correctly", func() {
-		//     By("simulating a valid update scenario")
-		//     oldObj.SomeRequiredField = "updated_value"
-		//     obj.SomeRequiredField = "updated_value"
-		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
-		// })
+		It("Should admit actions targeting any namespace", func() {
+			obj.Spec.Actions.OnOpen = []gateshv1alpha1.GateAction{{
+				Name:   "Unpause",
+				Target: gateshv1alpha1.GateActionTarget{ApiVersion: "apps/v1", Kind: "Deployment", Namespace: "other", Name: "app"},
+				Patch:  apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":false}}`)},
+			}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit protections selecting any namespace", func() {
+			obj.Spec.Protect = []gateshv1alpha1.GateProtection{{
+				Selector: gateshv1alpha1.GateTargetSelector{ApiVersion: "v1", Kind: "Secret", Namespace: "kube-system"},
+			}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
 	})
 
 })
@@ -27,9 +27,16 @@ import (
 var DefaultRequeueAfter = &metav1.Duration{Duration: 60 * time.Second}
 var DefaultConsolidationDelay = &metav1.Duration{Duration: 5 * time.Second}
 var DefaultConsolidationCount = 1
+var DefaultTimeoutPolicy = gateshv1alpha1.GateTimeoutPolicyStop
+var DefaultSpecChangePolicy = gateshv1alpha1.GateSpecChangePolicyKeepOpened
+var DefaultFlappingWindow = &metav1.Duration{Duration: 10 * time.Minute}
+var DefaultTargetOnError = gateshv1alpha1.GateTargetOnErrorFalse
+var DefaultTargetMaxStaleness = &metav1.Duration{Duration: 5 * time.Minute}
 var DefaultTargetValidators = []gateshv1alpha1.GateTargetValidator{{AtLeast: gateshv1alpha1.GateTargetValidatorAtLeast{Count: 1, Percent: 0}}}
 var DefaultOperationOperator = gateshv1alpha1.GateOperatorAnd
 var DefaultMatchConditionStatus = metav1.ConditionTrue
+var DefaultActionPatchType = gateshv1alpha1.GateActionPatchTypeMerge
+var DefaultExportConfigMapName = "gate-states"
 
 func ApplyDefaultSpec(spec *gateshv1alpha1.GateSpec) {
 	if spec.EvaluationPeriod == nil {
@@ -41,6 +48,26 @@ func ApplyDefaultSpec(spec *gateshv1alpha1.GateSpec) {
 	if spec.Consolidation.Delay == nil {
 		spec.Consolidation.Delay = DefaultConsolidationDelay
 	}
+	if spec.CloseConsolidation.Count == 0 {
+		spec.CloseConsolidation.Count = DefaultConsolidationCount
+	}
+	if spec.CloseConsolidation.Delay == nil {
+		spec.CloseConsolidation.Delay = DefaultConsolidationDelay
+	}
+	if spec.TimeoutPolicy == "" {
+		spec.TimeoutPolicy = DefaultTimeoutPolicy
+	}
+	if spec.SpecChangePolicy == "" {
+		spec.SpecChangePolicy = DefaultSpecChangePolicy
+	}
+	if spec.Flapping != nil {
+		if spec.Flapping.Window == nil {
+			spec.Flapping.Window = DefaultFlappingWindow
+		}
+		if spec.Flapping.QuietPeriod == nil {
+			spec.Flapping.QuietPeriod = spec.Flapping.Window
+		}
+	}
 	if spec.Operation.Operator == "" {
 		spec.Operation.Operator = DefaultOperationOperator
 	}
@@ -48,6 +75,12 @@ func ApplyDefaultSpec(spec *gateshv1alpha1.GateSpec) {
 		if spec.Targets[idx].Name == "" {
 			spec.Targets[idx].Name = "Target" + strconv.Itoa(idx+1)
 		}
+		if spec.Targets[idx].OnError == "" {
+			spec.Targets[idx].OnError = DefaultTargetOnError
+		}
+		if spec.Targets[idx].OnError == gateshv1alpha1.GateTargetOnErrorKeepLastResult && spec.Targets[idx].MaxStaleness == nil {
+			spec.Targets[idx].MaxStaleness = DefaultTargetMaxStaleness
+		}
 		if spec.Targets[idx].Validators == nil {
 			spec.Targets[idx].Validators = DefaultTargetValidators
 		} else {
@@ -58,4 +91,20 @@ func ApplyDefaultSpec(spec *gateshv1alpha1.GateSpec) {
 			}
 		}
 	}
+	ApplyDefaultActions(spec.Actions.OnOpen)
+	ApplyDefaultActions(spec.Actions.OnClose)
+	if spec.Export.ConfigMap != nil && spec.Export.ConfigMap.Name == "" {
+		spec.Export.ConfigMap.Name = DefaultExportConfigMapName
+	}
+}
+
+func ApplyDefaultActions(actions []gateshv1alpha1.GateAction) {
+	for idx := range actions {
+		if actions[idx].Name == "" {
+			actions[idx].Name = "Action" + strconv.Itoa(idx+1)
+		}
+		if actions[idx].PatchType == "" {
+			actions[idx].PatchType = DefaultActionPatchType
+		}
+	}
 }
@@ -0,0 +1,194 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WaitForAnnotatedKinds are the kinds of objects inspected for the wait-for annotation.
+var WaitForAnnotatedKinds = []schema.GroupVersionKind{
+	{Group: "", Version: "v1", Kind: "Pod"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+}
+
+// WaitForIndexField indexes the objects of the WaitForAnnotatedKinds by the gates referenced in their wait-for
+// annotation, so that the dependents of a gate are found without listing all the objects of the cluster.
+const WaitForIndexField = "metadata.annotations.waitFor"
+
+// SetupWaitForIndexes registers the WaitForIndexField index of the WaitForAnnotatedKinds.
+func SetupWaitForIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, gvk := range WaitForAnnotatedKinds {
+		object := &metav1.PartialObjectMetadata{}
+		object.SetGroupVersionKind(gvk)
+		if err := indexer.IndexField(ctx, object, WaitForIndexField, IndexWaitFor); err != nil {
+			return fmt.Errorf("failed to index %s: %w", gvk.Kind, err)
+		}
+	}
+	return nil
+}
+
+// IndexWaitFor returns the keys of the gates referenced by the wait-for annotation of the object.
+func IndexWaitFor(object client.Object) []string {
+	annotation, ok := object.GetAnnotations()[gateshv1alpha1.GateWaitForAnnotation]
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0)
+	for _, entry := range strings.Split(annotation, ",") {
+		keys = append(keys, GetWaitForKey(ParseWaitForEntry(strings.TrimSpace(entry), object.GetNamespace())))
+	}
+	return keys
+}
+
+// GetWaitForKey returns the WaitForIndexField key of a gate.
+func GetWaitForKey(kind string, namespace string, name string) string {
+	if kind == "ClusterGate" {
+		return fmt.Sprintf("ClusterGate/%s", name)
+	}
+	return fmt.Sprintf("Gate/%s/%s", namespace, name)
+}
+
+// ValidateGateDeletion looks for the dependents of the gate being deleted. Depending on the deletion policy
+// annotation of the gate, the deletion is either denied or admitted with a warning listing the dependents. The gates of
+// a namespace being deleted are always admitted, so that they don't block the deletion of the namespace.
+func ValidateGateDeletion(ctx context.Context, reader client.Reader, kind string, gate metav1.Object) (admission.Warnings, error) {
+	if reader == nil {
+		return nil, nil
+	}
+
+	deny := gate.GetAnnotations()[gateshv1alpha1.GateDeletionPolicyAnnotation] == gateshv1alpha1.GateDeletionPolicyDeny
+	if deny && kind == "Gate" && IsNamespaceTerminating(ctx, reader, gate.GetNamespace()) {
+		deny = false
+	}
+	dependents, err := FindGateDependents(ctx, reader, kind, gate)
+	if err != nil {
+		if deny {
+			return nil, fmt.Errorf("unable to look for the dependents of %s %s: %w", kind, gate.GetName(), err)
+		}
+		return admission.Warnings{fmt.Sprintf("unable to look for the dependents of %s %s: %s", kind, gate.GetName(), err.Error())}, nil
+	}
+	if len(dependents) == 0 {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("%s %s has dependents: %s", kind, gate.GetName(), strings.Join(dependents, ", "))
+	if deny {
+		return nil, fmt.Errorf("%s (remove them or set the %s annotation to %s)", message, gateshv1alpha1.GateDeletionPolicyAnnotation, gateshv1alpha1.GateDeletionPolicyWarn)
+	}
+	return admission.Warnings{message}, nil
+}
+
+// IsNamespaceTerminating returns true if the namespace is being deleted. A namespace that can't be read is considered
+// alive.
+func IsNamespaceTerminating(ctx context.Context, reader client.Reader, name string) bool {
+	var namespace corev1.Namespace
+	if err := reader.Get(ctx, client.ObjectKey{Name: name}, &namespace); err != nil {
+		return false
+	}
+	return namespace.DeletionTimestamp != nil
+}
+
+// FindGateDependents lists the gates whose targets select the given gate, and the objects waiting for it through the
+// wait-for annotation. The reader must serve the WaitForIndexField index. Dependents being deleted are ignored.
+func FindGateDependents(ctx context.Context, reader client.Reader, kind string, gate metav1.Object) ([]string, error) {
+	dependents := make([]string, 0)
+
+	var gates gateshv1alpha1.GateList
+	if err := reader.List(ctx, &gates); err != nil {
+		return nil, fmt.Errorf("failed to list Gates: %w", err)
+	}
+	for _, dependent := range gates.Items {
+		if dependent.DeletionTimestamp != nil || (kind == "Gate" && dependent.Namespace == gate.GetNamespace() && dependent.Name == gate.GetName()) {
+			continue
+		}
+		for _, target := range dependent.Spec.Targets {
+			if IsGateSelectedBy(kind, gate, target.Selector, dependent.Namespace) {
+				dependents = append(dependents, fmt.Sprintf("Gate %s/%s (target %s)", dependent.Namespace, dependent.Name, target.Name))
+			}
+		}
+	}
+
+	var clusterGates gateshv1alpha1.ClusterGateList
+	if err := reader.List(ctx, &clusterGates); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterGates: %w", err)
+	}
+	for _, dependent := range clusterGates.Items {
+		if dependent.DeletionTimestamp != nil || (kind == "ClusterGate" && dependent.Name == gate.GetName()) {
+			continue
+		}
+		for _, target := range dependent.Spec.Targets {
+			if IsGateSelectedBy(kind, gate, target.Selector, "") {
+				dependents = append(dependents, fmt.Sprintf("ClusterGate %s (target %s)", dependent.Name, target.Name))
+			}
+		}
+	}
+
+	key := GetWaitForKey(kind, gate.GetNamespace(), gate.GetName())
+	for _, gvk := range WaitForAnnotatedKinds {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := reader.List(ctx, list, client.MatchingFields{WaitForIndexField: key}); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+		for _, object := range list.Items {
+			if object.DeletionTimestamp != nil {
+				continue
+			}
+			dependents = append(dependents, fmt.Sprintf("%s %s/%s (annotation %s)", gvk.Kind, object.Namespace, object.Name, gateshv1alpha1.GateWaitForAnnotation))
+		}
+	}
+
+	return dependents, nil
+}
+
+// IsGateSelectedBy checks whether the target selector of a gate living in the given namespace selects the gate.
+func IsGateSelectedBy(kind string, gate metav1.Object, selector gateshv1alpha1.GateTargetSelector, namespace string) bool {
+	gv, err := schema.ParseGroupVersion(selector.ApiVersion)
+	if err != nil || gv.Group != gateshv1alpha1.GroupVersion.Group || selector.Kind != kind {
+		return false
+	}
+
+	if kind == "Gate" {
+		if selector.Namespace != "" {
+			namespace = selector.Namespace
+		}
+		// An empty namespace means a ClusterGate listing gates in all the namespaces.
+		if namespace != "" && namespace != gate.GetNamespace() {
+			return false
+		}
+	}
+
+	if selector.Name != "" {
+		return selector.Name == gate.GetName()
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+	if err != nil || labelSelector.Empty() {
+		return false
+	}
+	return labelSelector.Matches(labels.Set(gate.GetLabels()))
+}
+
+// ParseWaitForEntry returns the kind, namespace and name of the gate referenced by an entry of the wait-for annotation.
+// Gates referenced without namespace live in the given default namespace.
+func ParseWaitForEntry(entry string, defaultNamespace string) (string, string, string) {
+	if name, ok := strings.CutPrefix(entry, "ClusterGate/"); ok {
+		return "ClusterGate", "", name
+	}
+	entry = strings.TrimPrefix(entry, "Gate/")
+	if namespace, name, ok := strings.Cut(entry, "/"); ok {
+		return "Gate", namespace, name
+	}
+	return "Gate", defaultNamespace, entry
+}
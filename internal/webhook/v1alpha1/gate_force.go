@@ -0,0 +1,52 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/robinlioret/gate-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// RecordForcedBy sets the gate.sh/forced-by annotation to the user who set or changed the gate.sh/force-state
+// annotation. The recorded user can't be changed while the forced state doesn't change, and is removed with it.
+func RecordForcedBy(ctx context.Context, gate metav1.Object) {
+	annotations := gate.GetAnnotations()
+	forcedState := annotations[v1alpha1.GateForceStateAnnotation]
+	if forcedState == "" {
+		if _, ok := annotations[v1alpha1.GateForcedByAnnotation]; ok {
+			delete(annotations, v1alpha1.GateForcedByAnnotation)
+			gate.SetAnnotations(annotations)
+		}
+		return
+	}
+
+	request, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return
+	}
+	var oldGate metav1.PartialObjectMetadata
+	if len(request.OldObject.Raw) > 0 {
+		if err := json.Unmarshal(request.OldObject.Raw, &oldGate); err != nil {
+			return
+		}
+	}
+	oldAnnotations := oldGate.GetAnnotations()
+	forcedBy := request.UserInfo.Username
+	if oldAnnotations[v1alpha1.GateForceStateAnnotation] == forcedState && oldAnnotations[v1alpha1.GateForcedByAnnotation] != "" {
+		forcedBy = oldAnnotations[v1alpha1.GateForcedByAnnotation]
+	}
+	annotations[v1alpha1.GateForcedByAnnotation] = forcedBy
+	gate.SetAnnotations(annotations)
+}
+
+// ValidateGateAnnotations checks the annotations controlling the gate.
+func ValidateGateAnnotations(gate metav1.Object) error {
+	forcedState, ok := gate.GetAnnotations()[v1alpha1.GateForceStateAnnotation]
+	if ok && forcedState != v1alpha1.GateStateOpened && forcedState != v1alpha1.GateStateClosed {
+		return fmt.Errorf("%s must be %s or %s: %s", v1alpha1.GateForceStateAnnotation, v1alpha1.GateStateOpened, v1alpha1.GateStateClosed, forcedState)
+	}
+	return nil
+}
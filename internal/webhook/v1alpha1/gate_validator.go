@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 
@@ -10,7 +11,8 @@ import (
 
 var PascalCaseRegex = regexp.MustCompile("^[A-Z][A-Za-z0-9]*$")
 
-func ValidateGateSpec(spec *v1alpha1.GateSpec) (admission.Warnings, error) {
+// ValidateGateSpec checks the spec of a gate. The namespace is the one of the gate, empty for ClusterGates.
+func ValidateGateSpec(spec *v1alpha1.GateSpec, namespace string) (admission.Warnings, error) {
 	for _, target := range spec.Targets {
 		if !PascalCaseRegex.MatchString(target.Name) {
 			return nil, fmt.Errorf("target name must be PascalCase: %s", target.Name)
@@ -23,5 +25,63 @@ func ValidateGateSpec(spec *v1alpha1.GateSpec) (admission.Warnings, error) {
 			}
 		}
 	}
+	if err := ValidateGateActions(spec.Actions.OnOpen, namespace); err != nil {
+		return nil, err
+	}
+	if err := ValidateGateActions(spec.Actions.OnClose, namespace); err != nil {
+		return nil, err
+	}
+	if err := ValidateGateProtections(spec.Protect, namespace); err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
+
+// ValidateGateProtections checks the protected objects of a gate. A Gate can only protect objects of its own namespace.
+func ValidateGateProtections(protections []v1alpha1.GateProtection, namespace string) error {
+	for idx, protection := range protections {
+		if namespace != "" && protection.Selector.Namespace != "" && protection.Selector.Namespace != namespace {
+			return fmt.Errorf("protection %d of a Gate can only select objects in its own namespace %s", idx+1, namespace)
+		}
+	}
+	return nil
+}
+
+// ValidateGateActions checks the actions of a gate. A Gate can only patch objects of its own namespace.
+func ValidateGateActions(actions []v1alpha1.GateAction, namespace string) error {
+	names := map[string]bool{}
+	for _, action := range actions {
+		if !PascalCaseRegex.MatchString(action.Name) {
+			return fmt.Errorf("action name must be PascalCase: %s", action.Name)
+		}
+		if names[action.Name] {
+			return fmt.Errorf("action name must be unique: %s", action.Name)
+		}
+		names[action.Name] = true
+		if action.Target.ApiVersion == "" || action.Target.Kind == "" || action.Target.Name == "" {
+			return fmt.Errorf("action %s must target an object by apiVersion, kind and name", action.Name)
+		}
+		if namespace != "" && action.Target.Namespace != "" && action.Target.Namespace != namespace {
+			return fmt.Errorf("action %s of a Gate can only target objects in its own namespace %s", action.Name, namespace)
+		}
+		var patch map[string]interface{}
+		if err := json.Unmarshal(action.Patch.Raw, &patch); err != nil || len(patch) == 0 {
+			return fmt.Errorf("action %s patch must be a non-empty object", action.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateGateExport checks the export of a gate. The namespace is the one of the gate, empty for ClusterGates.
+func ValidateGateExport(export *v1alpha1.GateExport, namespace string) error {
+	if export.ConfigMap == nil {
+		return nil
+	}
+	if namespace == "" && export.ConfigMap.Namespace == "" {
+		return fmt.Errorf("export configMap namespace is required for a ClusterGate")
+	}
+	if namespace != "" && export.ConfigMap.Namespace != "" && export.ConfigMap.Namespace != namespace {
+		return fmt.Errorf("a Gate can only export its state in its own namespace %s", namespace)
+	}
+	return nil
+}
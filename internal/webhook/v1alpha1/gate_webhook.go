@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -36,7 +37,7 @@ var gatelog = logf.Log.WithName("gate-resource")
 // SetupGateWebhookWithManager registers the webhook for Gate in the manager.
 func SetupGateWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&gateshv1alpha1.Gate{}).
-		WithValidator(&GateCustomValidator{}).
+		WithValidator(&GateCustomValidator{Reader: mgr.GetClient()}).
 		WithDefaulter(&GateCustomDefaulter{}).
 		Complete()
 }
@@ -55,7 +56,7 @@ type GateCustomDefaulter struct{}
 var _ webhook.CustomDefaulter = &GateCustomDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Gate.
-func (d *GateCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *GateCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	gate, ok := obj.(*gateshv1alpha1.Gate)
 
 	if !ok {
@@ -63,20 +64,23 @@ func (d *GateCustomDefaulter) Default(_ context.Context, obj runtime.Object) err
 	}
 	gatelog.Info("Defaulting for Gate", "name", gate.GetName())
 	ApplyDefaultSpec(&gate.Spec)
+	RecordForcedBy(ctx, gate)
 	return nil
 }
 
-// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
 // Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
-// +kubebuilder:webhook:path=/validate-gate-sh-v1alpha1-gate,mutating=false,failurePolicy=fail,sideEffects=None,groups=gate.sh,resources=gates,verbs=create;update,versions=v1alpha1,name=vgate-v1alpha1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-gate-sh-v1alpha1-gate,mutating=false,failurePolicy=fail,sideEffects=None,groups=gate.sh,resources=gates,verbs=create;update;delete,versions=v1alpha1,name=vgate-v1alpha1.kb.io,admissionReviewVersions=v1
 
 // GateCustomValidator struct is responsible for validating the Gate resource
 // when it is created, updated, or deleted.
 //
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as this struct is used only for temporary operations and does not need to be deeply copied.
-type GateCustomValidator struct{}
+type GateCustomValidator struct {
+	// Reader is used to look for the dependents of the Gate upon deletion. It must serve the WaitForIndexField index.
+	Reader client.Reader
+}
 
 var _ webhook.CustomValidator = &GateCustomValidator{}
 
@@ -87,7 +91,13 @@ func (v *GateCustomValidator) ValidateCreate(_ context.Context, obj runtime.Obje
 		return nil, fmt.Errorf("expected a Gate object but got %T", obj)
 	}
 	gatelog.Info("Validation for Gate upon creation", "name", gate.GetName())
-	return ValidateGateSpec(&gate.Spec)
+	if err := ValidateGateExport(&gate.Spec.Export, gate.GetNamespace()); err != nil {
+		return nil, err
+	}
+	if err := ValidateGateAnnotations(gate); err != nil {
+		return nil, err
+	}
+	return ValidateGateSpec(&gate.Spec, gate.GetNamespace())
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Gate.
@@ -97,7 +107,13 @@ func (v *GateCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj r
 		return nil, fmt.Errorf("expected a Gate object for the newObj but got %T", newObj)
 	}
 	gatelog.Info("Validation for Gate upon update", "name", gate.GetName())
-	return ValidateGateSpec(&gate.Spec)
+	if err := ValidateGateExport(&gate.Spec.Export, gate.GetNamespace()); err != nil {
+		return nil, err
+	}
+	if err := ValidateGateAnnotations(gate); err != nil {
+		return nil, err
+	}
+	return ValidateGateSpec(&gate.Spec, gate.GetNamespace())
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Gate.
@@ -107,8 +123,5 @@ func (v *GateCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Ob
 		return nil, fmt.Errorf("expected a Gate object but got %T", obj)
 	}
 	gatelog.Info("Validation for Gate upon deletion", "name", gate.GetName())
-
-	// TODO(user): fill in your validation logic upon object deletion.
-
-	return nil, nil
+	return ValidateGateDeletion(ctx, v.Reader, "Gate", gate)
 }
@@ -17,11 +17,24 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+	"encoding/json"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	gateshv1alpha1 "github.com/robinlioret/gate-operator/api/v1alpha1"
-	// TODO (user): Add any additional imports if needed
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var _ = Describe("Gate Webhook", func() {
@@ -62,26 +75,192 @@ var _ = Describe("Gate Webhook", func() {
 	})
 
 	Context("When creating or updating Gate under Validating Webhook", func() {
-		// TODO (user): Add logic for validating webhooks
-		// Example:
-		// It("Should deny creation if a required field is missing", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = ""
-		//     Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
-		// })
-		//
-		// It("Should admit creation if all required fields are present", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = "valid_value"
-		//     Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
-		// })
-		//
-		// It("Should validate updates correctly", func() {
-		//     By("simulating a valid update scenario")
-		//     oldObj.SomeRequiredField = "updated_value"
-		//     obj.SomeRequiredField = "updated_value"
-		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
-		// })
+		BeforeEach(func() {
+			obj.Namespace = "default"
+			oldObj.Namespace = "default"
+		})
+
+		action := func(name string, namespace string) gateshv1alpha1.GateAction {
+			return gateshv1alpha1.GateAction{
+				Name:   name,
+				Target: gateshv1alpha1.GateActionTarget{ApiVersion: "apps/v1", Kind: "Deployment", Namespace: namespace, Name: "app"},
+				Patch:  apiextensionsv1.JSON{Raw: []byte(`{"spec":{"paused":false}}`)},
+			}
+		}
+
+		It("Should admit actions targeting the namespace of the gate", func() {
+			obj.Spec.Actions.OnOpen = []gateshv1alpha1.GateAction{action("Unpause", ""), action("UnpauseAgain", "default")}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny actions targeting another namespace", func() {
+			obj.Spec.Actions.OnClose = []gateshv1alpha1.GateAction{action("Pause", "other")}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("own namespace default")))
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny invalid actions", func() {
+			obj.Spec.Actions.OnOpen = []gateshv1alpha1.GateAction{action("unpause", "")}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("PascalCase")))
+
+			obj.Spec.Actions.OnOpen = []gateshv1alpha1.GateAction{action("Unpause", ""), action("Unpause", "")}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("unique")))
+
+			noTarget := action("Unpause", "")
+			noTarget.Target.Name = ""
+			obj.Spec.Actions.OnOpen = []gateshv1alpha1.GateAction{noTarget}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("apiVersion, kind and name")))
+
+			emptyPatch := action("Unpause", "")
+			emptyPatch.Patch.Raw = []byte(`{}`)
+			obj.Spec.Actions.OnOpen = []gateshv1alpha1.GateAction{emptyPatch}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("non-empty object")))
+		})
+
+		It("Should deny protections selecting another namespace", func() {
+			protection := func(namespace string) gateshv1alpha1.GateProtection {
+				return gateshv1alpha1.GateProtection{Selector: gateshv1alpha1.GateTargetSelector{ApiVersion: "v1", Kind: "Secret", Namespace: namespace}}
+			}
+			obj.Spec.Protect = []gateshv1alpha1.GateProtection{protection(""), protection("default")}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Protect = append(obj.Spec.Protect, protection("kube-system"))
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("protection 3")))
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(MatchError(ContainSubstring("own namespace default")))
+		})
+
+		It("Should deny exports in another namespace", func() {
+			obj.Spec.Export.ConfigMap = &gateshv1alpha1.GateExportConfigMap{Name: "states", Namespace: "default"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Export.ConfigMap.Namespace = "other"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("own namespace default")))
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny an invalid forced state", func() {
+			obj.Annotations = map[string]string{gateshv1alpha1.GateForceStateAnnotation: gateshv1alpha1.GateStateClosed}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Annotations[gateshv1alpha1.GateForceStateAnnotation] = gateshv1alpha1.GateStateFailed
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring(gateshv1alpha1.GateForceStateAnnotation)))
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(HaveOccurred())
+		})
+	})
+
+	Context("When forcing the state of a Gate under Defaulting Webhook", func() {
+		request := func(username string, old *gateshv1alpha1.Gate) context.Context {
+			raw, err := json.Marshal(old)
+			Expect(err).NotTo(HaveOccurred())
+			return admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo:  authenticationv1.UserInfo{Username: username},
+				OldObject: runtime.RawExtension{Raw: raw},
+			}})
+		}
+
+		It("Should record the user who forced the state", func() {
+			obj.Annotations = map[string]string{gateshv1alpha1.GateForceStateAnnotation: gateshv1alpha1.GateStateOpened}
+			Expect(defaulter.Default(request("alice", oldObj), obj)).To(Succeed())
+			Expect(obj.Annotations).To(HaveKeyWithValue(gateshv1alpha1.GateForcedByAnnotation, "alice"))
+
+			By("keeping the user while the forced state doesn't change")
+			oldObj.Annotations = map[string]string{
+				gateshv1alpha1.GateForceStateAnnotation: gateshv1alpha1.GateStateOpened,
+				gateshv1alpha1.GateForcedByAnnotation:   "alice",
+			}
+			obj.Annotations[gateshv1alpha1.GateForcedByAnnotation] = "bob"
+			Expect(defaulter.Default(request("bob", oldObj), obj)).To(Succeed())
+			Expect(obj.Annotations).To(HaveKeyWithValue(gateshv1alpha1.GateForcedByAnnotation, "alice"))
+
+			By("recording the user who changed the forced state")
+			obj.Annotations[gateshv1alpha1.GateForceStateAnnotation] = gateshv1alpha1.GateStateClosed
+			Expect(defaulter.Default(request("bob", oldObj), obj)).To(Succeed())
+			Expect(obj.Annotations).To(HaveKeyWithValue(gateshv1alpha1.GateForcedByAnnotation, "bob"))
+
+			By("removing the user with the forced state")
+			delete(obj.Annotations, gateshv1alpha1.GateForceStateAnnotation)
+			Expect(defaulter.Default(request("bob", oldObj), obj)).To(Succeed())
+			Expect(obj.Annotations).NotTo(HaveKey(gateshv1alpha1.GateForcedByAnnotation))
+		})
+	})
+
+	Context("When deleting Gate under Validating Webhook", func() {
+		var scheme *runtime.Scheme
+
+		BeforeEach(func() {
+			obj.Name = "database"
+			obj.Namespace = "default"
+			scheme = runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(gateshv1alpha1.AddToScheme(scheme)).To(Succeed())
+		})
+
+		build := func(objects ...client.Object) client.Client {
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...)
+			for _, gvk := range WaitForAnnotatedKinds {
+				object := &metav1.PartialObjectMetadata{}
+				object.SetGroupVersionKind(gvk)
+				builder = builder.WithIndex(object, WaitForIndexField, IndexWaitFor)
+			}
+			return builder.Build()
+		}
+
+		It("Should admit the deletion of a gate without dependents", func() {
+			validator.Reader = build(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Namespace:   "default",
+				Annotations: map[string]string{gateshv1alpha1.GateWaitForAnnotation: "other/database"},
+			}})
+			Expect(validator.ValidateDelete(ctx, obj)).To(BeEmpty())
+		})
+
+		It("Should warn about the dependents of the gate", func() {
+			dependent := &gateshv1alpha1.Gate{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: gateshv1alpha1.GateSpec{Targets: []gateshv1alpha1.GateTarget{{
+					Name:     "Database",
+					Selector: gateshv1alpha1.GateTargetSelector{ApiVersion: "gate.sh/v1alpha1", Kind: "Gate", Name: "database"},
+				}}},
+			}
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Namespace:   "other",
+				Annotations: map[string]string{gateshv1alpha1.GateWaitForAnnotation: "cache, default/database"},
+			}}
+			validator.Reader = build(dependent, deployment)
+
+			warnings, err := validator.ValidateDelete(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring("Gate default/app (target Database)"))
+			Expect(warnings[0]).To(ContainSubstring("Deployment other/app (annotation gate.sh/wait-for)"))
+
+			By("denying the deletion with the deny policy")
+			obj.Annotations = map[string]string{gateshv1alpha1.GateDeletionPolicyAnnotation: gateshv1alpha1.GateDeletionPolicyDeny}
+			Expect(validator.ValidateDelete(ctx, obj)).Error().To(MatchError(ContainSubstring("has dependents")))
+		})
+
+		It("Should only warn about the dependents when the namespace of the gate is being deleted", func() {
+			obj.Annotations = map[string]string{gateshv1alpha1.GateDeletionPolicyAnnotation: gateshv1alpha1.GateDeletionPolicyDeny}
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:              "default",
+				DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+				Finalizers:        []string{"kubernetes"},
+			}}
+			dependent := &gateshv1alpha1.Gate{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: gateshv1alpha1.GateSpec{Targets: []gateshv1alpha1.GateTarget{{
+					Name:     "Database",
+					Selector: gateshv1alpha1.GateTargetSelector{ApiVersion: "gate.sh/v1alpha1", Kind: "Gate", Name: "database"},
+				}}},
+			}
+			validator.Reader = build(namespace, dependent)
+
+			warnings, err := validator.ValidateDelete(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("Gate default/app (target Database)")))
+		})
 	})
 
 })
@@ -109,6 +109,9 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
+	err = SetupWaitForIndexes(ctx, mgr.GetFieldIndexer())
+	Expect(err).NotTo(HaveOccurred())
+
 	err = SetupGateWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 